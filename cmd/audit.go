@@ -1,12 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	"github.com/ugolbck/seofordev/internal/audit"
+	"github.com/ugolbck/seofordev/internal/config"
+	"github.com/ugolbck/seofordev/internal/crawler"
 	"github.com/ugolbck/seofordev/internal/export"
 	"github.com/ugolbck/seofordev/internal/services"
+	"github.com/ugolbck/seofordev/internal/urlnorm"
 )
 
 var auditCmd = &cobra.Command{
@@ -17,65 +27,212 @@ var auditCmd = &cobra.Command{
 Examples:
   seo audit run                    # Audit localhost:3000
   seo audit run --port 8080        # Audit localhost:8080
+  seo audit run --url https://staging.example.com/docs  # Audit any URL
   seo audit list                   # Show audit history
   seo audit show <audit-id>        # Show audit details
+  seo audit resume <audit-id>      # Continue an interrupted audit
   seo audit export <audit-id>      # Export audit as AI prompt`,
 }
 
 var auditRunCmd = &cobra.Command{
 	Use:   "run",
-	Short: "Run a localhost SEO audit",
-	Long: `Run an SEO audit on your localhost development server. Specify the port with --port flag.
+	Short: "Run an SEO audit on localhost or any URL",
+	Long: `Run an SEO audit on your localhost development server. Specify the port with --port flag,
+or audit any base URL (staging previews, Docker hostnames, https dev servers) with --url.
+When the URL has a path, the crawl stays under that path.
 
 Examples:
   seo audit run                           # Audit localhost:3000 (default)
   seo audit run --port 8080              # Audit localhost:8080
-  seo audit run --port 3000 --max-pages 50  # Audit with custom limits`,
+  seo audit run --port 3000 --max-pages 50  # Audit with custom limits
+  seo audit run --renderer http           # Skip the browser for server-rendered sites
+  seo audit run --block-resources image,font,media  # Faster browser crawl, DOM only
+  seo audit run --url https://staging.example.com --rate 2  # At most 2 requests/sec
+  seo audit run --profile googlebot-smartphone  # Crawl as Googlebot on a phone
+  seo audit run --device both             # Compare mobile and desktop renders
+  seo audit run --budget lcp=2s,transfer=1MB  # Tighter performance budget
+  seo audit run --screenshots             # Save a PNG of every page with the audit
+  seo audit run --compare-raw             # Flag SEO content that only exists after JavaScript runs
+  seo audit run --strip-params sessionid,sort  # Treat URLs differing only in these parameters as one page
+  seo audit run --include "/blog/**" --exclude "/blog/tag/**"  # Only audit blog posts
+  seo audit run --urls-file paths.txt     # Audit only the pages listed in paths.txt
+  printf "/\n/pricing\n" | seo audit run --urls-file -  # Read the list from stdin
+  seo audit run --exec "npm run dev" --wait-for http://localhost:3000  # Start the dev server first
+  seo audit run --url http://web:8080     # Audit a Docker hostname
+  seo audit run --url https://localhost:8443/docs --insecure  # Only /docs, self-signed cert
+  seo audit run --header "Authorization: Bearer $TOKEN"     # Send an extra header
+  seo audit run --storage-state auth.json                   # Reuse a Playwright login session
+  seo audit run --login-script login.yml                    # Log in with a form before crawling
+
+Authentication can also be set in the auth section of ~/.seo/config.yml, performance
+budgets in its budgets section and URL canonicalization in url_normalization.
+A --urls-file lists one URL or path per line (blank lines and # comments are skipped);
+those pages are audited without following their links or reading sitemaps. Paths resolve
+like links (/pricing against the host, pricing against the --url path) and must stay
+under the --url path.
+With --exec, the command is started before crawling (its output goes to devserver.log in
+the audit directory), the audit waits until --wait-for (default: the audited URL) responds,
+and the command and everything it started are stopped when the audit ends.
+Tracking parameters (utm_*, gclid, fbclid, ...) are ignored and query parameters sorted
+by default. robots.txt is obeyed; if it is unreachable or returns 5xx nothing is crawled,
+as RFC 9309 requires, unless --ignore-robots-errors is set.`,
 	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.LoadOrCreateConfig()
+		if err != nil {
+			log.Fatal("Failed to load config", "error", err)
+		}
+
 		port, _ := cmd.Flags().GetInt("port")
 		concurrency, _ := cmd.Flags().GetInt("concurrency")
 		maxPages, _ := cmd.Flags().GetInt("max-pages")
 		maxDepth, _ := cmd.Flags().GetInt("max-depth")
-		ignorePatterns, _ := cmd.Flags().GetStringSlice("ignore")
+		includePatterns, excludePatterns, err := patternsFromFlags(cmd, cfg)
+		if err != nil {
+			log.Fatal("Invalid URL patterns", "error", err)
+		}
+		renderer, _ := cmd.Flags().GetString("renderer")
+		targetURL, _ := cmd.Flags().GetString("url")
+		insecure, _ := cmd.Flags().GetBool("insecure")
+		blockResources, _ := cmd.Flags().GetStringSlice("block-resources")
+		rate, _ := cmd.Flags().GetFloat64("rate")
+		robotsAgent, _ := cmd.Flags().GetString("robots-agent")
+		profile, _ := cmd.Flags().GetString("profile")
+		device, _ := cmd.Flags().GetString("device")
+		screenshots, _ := cmd.Flags().GetBool("screenshots")
+		compareRaw, _ := cmd.Flags().GetBool("compare-raw")
+		ignoreRobotsErrors, _ := cmd.Flags().GetBool("ignore-robots-errors")
+		execCommand, _ := cmd.Flags().GetString("exec")
+		waitFor, _ := cmd.Flags().GetString("wait-for")
+		waitTimeout, _ := cmd.Flags().GetDuration("wait-timeout")
+		if rate < 0 {
+			log.Fatal("Invalid rate", "rate", rate)
+		}
 
-		baseURL := fmt.Sprintf("http://localhost:%d", port)
+		auth, err := authConfigFromFlags(cmd, cfg)
+		if err != nil {
+			log.Fatal("Invalid authentication options", "error", err)
+		}
 
-		log.Info("Starting localhost SEO audit", "port", port, "url", baseURL)
+		budget, err := budgetFromFlags(cmd, cfg)
+		if err != nil {
+			log.Fatal("Invalid performance budget", "error", err)
+		}
 
-		auditService, err := services.NewAuditService()
+		urlPolicy, err := urlPolicyFromFlags(cmd, cfg)
 		if err != nil {
-			log.Fatal("Failed to initialize audit service", "error", err)
+			log.Fatal("Invalid URL normalization options", "error", err)
 		}
 
 		config := services.AuditConfig{
-			Port:           port,
-			Concurrency:    concurrency,
-			MaxPages:       maxPages,
-			MaxDepth:       maxDepth,
-			IgnorePatterns: ignorePatterns,
+			Port:            port,
+			URL:             targetURL,
+			Insecure:        insecure,
+			Auth:            auth,
+			Concurrency:     concurrency,
+			MaxPages:        maxPages,
+			MaxDepth:        maxDepth,
+			IgnorePatterns:  excludePatterns,
+			IncludePatterns: includePatterns,
+			Renderer:        renderer,
+			BlockResources:  blockResources,
+			Rate:            rate,
+			RobotsAgent:     robotsAgent,
+			Profile:         profile,
+			Device:          device,
+			Screenshots:     screenshots,
+			CompareRaw:      compareRaw,
+			RobotsFailOpen:  ignoreRobotsErrors,
+			Budget:          budget,
+			URLPolicy:       urlPolicy,
+			Exec:            execCommand,
+			WaitFor:         waitFor,
+			WaitTimeout:     waitTimeout,
 		}
 
-		result, err := auditService.RunAudit(baseURL, config)
+		baseURL, err := config.BaseURL()
+		if err != nil {
+			log.Fatal("Invalid audit target", "error", err)
+		}
+
+		if urlsFile, _ := cmd.Flags().GetString("urls-file"); urlsFile != "" {
+			config.URLs, err = crawler.ReadURLFile(urlsFile, baseURL)
+			if err != nil {
+				log.Fatal("Invalid URL list", "file", urlsFile, "error", err)
+			}
+			log.Info("Starting SEO audit", "url", baseURL, "listed_urls", len(config.URLs))
+		} else {
+			log.Info("Starting SEO audit", "url", baseURL)
+		}
+
+		auditService, err := services.NewAuditService()
+		if err != nil {
+			log.Fatal("Failed to initialize audit service", "error", err)
+		}
+
+		progress := newAuditProgress()
+		auditService.OnEvent = progress.handle
+
+		ctx, cancel := interruptContext()
+		defer cancel()
+
+		result, err := auditService.RunAudit(ctx, baseURL, config)
+		progress.finish()
 		if err != nil {
 			log.Fatal("Audit failed", "error", err)
 		}
 
-		overallScore := "N/A"
-		if result.OverallScore != nil {
-			overallScore = fmt.Sprintf("%.1f/100", *result.OverallScore)
+		printAuditComplete(result)
+	},
+}
+
+var auditResumeCmd = &cobra.Command{
+	Use:   "resume <audit-id>",
+	Short: "Continue an interrupted audit",
+	Long: `Continue an audit that was interrupted before it completed. The crawl picks up from
+its last checkpoint with the same settings, and pages already analyzed are kept.
+
+Header values and basic auth passwords are never stored with an audit, so pass them
+again (or keep them in ~/.seo/config.yml). Cookie, storage state and login script
+files are reused from the original run.
+
+Examples:
+  seo audit resume 1a2b3c4d
+  seo audit resume 1a2b3c4d --header "Authorization: Bearer $TOKEN"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		auditID := args[0]
+
+		cfg, err := config.LoadOrCreateConfig()
+		if err != nil {
+			log.Fatal("Failed to load config", "error", err)
+		}
+
+		auth, err := authConfigFromFlags(cmd, cfg)
+		if err != nil {
+			log.Fatal("Invalid authentication options", "error", err)
 		}
 
-		log.Info("Audit completed successfully", 
-			"audit_id", result.ID,
-			"pages_analyzed", result.PagesAnalyzed,
-			"overall_score", overallScore)
+		auditService, err := services.NewAuditService()
+		if err != nil {
+			log.Fatal("Failed to initialize audit service", "error", err)
+		}
 
-		fmt.Printf("\n✅ Audit complete!\n")
-		fmt.Printf("   ID: %s\n", result.ID)
-		fmt.Printf("   Pages analyzed: %d\n", result.PagesAnalyzed)
-		fmt.Printf("   Overall score: %s\n", overallScore)
-		fmt.Printf("\nView details: seo audit show %s\n", result.ID)
+		log.Info("Resuming SEO audit", "audit_id", auditID)
+
+		progress := newAuditProgress()
+		auditService.OnEvent = progress.handle
+
+		ctx, cancel := interruptContext()
+		defer cancel()
+
+		result, err := auditService.ResumeAudit(ctx, auditID, auth)
+		progress.finish()
+		if err != nil {
+			log.Fatal("Audit failed", "audit_id", auditID, "error", err)
+		}
+
+		printAuditComplete(result)
 	},
 }
 
@@ -103,7 +260,11 @@ var auditListCmd = &cobra.Command{
 		
 		for _, audit := range audits {
 			status := "✅"
-			if audit.Status != "completed" {
+			if audit.Status == "partial" {
+				status = "⏸️ "
+			} else if audit.Status == "failed" {
+				status = "❌"
+			} else if audit.Status != "completed" {
 				status = "⏳"
 			}
 
@@ -114,7 +275,13 @@ var auditListCmd = &cobra.Command{
 
 			fmt.Printf("%s %s - %s\n", status, audit.ID[:8], audit.BaseURL)
 			fmt.Printf("    Created: %s\n", audit.CreatedAt.Format("Jan 2, 2006 15:04"))
-			fmt.Printf("    Score: %s, Pages: %d\n\n", score, audit.PagesAnalyzed)
+			if audit.Status == "partial" {
+				fmt.Printf("    Score: %s, Pages: %d (partial - interrupted)\n\n", score, audit.PagesAnalyzed)
+			} else if audit.Status == "failed" {
+				fmt.Printf("    Score: %s, Pages: %d (failed)\n\n", score, audit.PagesAnalyzed)
+			} else {
+				fmt.Printf("    Score: %s, Pages: %d\n\n", score, audit.PagesAnalyzed)
+			}
 		}
 
 		fmt.Printf("Use 'seo audit show <id>' to view details\n")
@@ -144,16 +311,52 @@ var auditShowCmd = &cobra.Command{
 		fmt.Printf("🆔 ID: %s\n", audit.ID)
 		fmt.Printf("🌐 URL: %s\n", audit.BaseURL)
 		fmt.Printf("📅 Created: %s\n", audit.CreatedAt.Format("January 2, 2006 at 15:04"))
+		if audit.Profile != "" {
+			fmt.Printf("🤖 Profile: %s\n", audit.Profile)
+		}
+		if audit.Device != "" {
+			fmt.Printf("📱 Device: %s\n", audit.Device)
+		}
+		if audit.ScreenshotDir != "" {
+			fmt.Printf("📸 Screenshots: %s\n", audit.ScreenshotDir)
+		}
+		if audit.ListedURLs > 0 {
+			fmt.Printf("📋 Mode: list (%d URLs)\n", audit.ListedURLs)
+		}
+		if audit.DevServerLog != "" {
+			fmt.Printf("🖥️  Dev server log: %s\n", audit.DevServerLog)
+		}
 		
-		if audit.CompletedAt != nil {
+		if audit.Status == "partial" {
+			fmt.Printf("⏸️  Partial: interrupted on %s (continue with: seo audit resume %s)\n",
+				audit.CompletedAt.Format("January 2, 2006 at 15:04"), audit.ID[:8])
+		} else if audit.Status == "failed" {
+			fmt.Printf("❌ Failed: %s (retry with: seo audit resume %s)\n",
+				audit.CompletedAt.Format("January 2, 2006 at 15:04"), audit.ID[:8])
+		} else if audit.CompletedAt != nil {
 			fmt.Printf("✅ Completed: %s\n", audit.CompletedAt.Format("January 2, 2006 at 15:04"))
+		} else {
+			fmt.Printf("⏳ Status: %s (continue with: seo audit resume %s)\n", audit.Status, audit.ID[:8])
 		}
 
 		if audit.OverallScore != nil {
 			fmt.Printf("📈 Overall Score: %.1f/100\n", *audit.OverallScore)
 		}
 		
-		fmt.Printf("📄 Pages Analyzed: %d\n\n", len(audit.Pages))
+		fmt.Printf("📄 Pages Analyzed: %d\n", len(audit.Pages))
+		if audit.Crawl != nil && audit.Crawl.PagesPerSecond > 0 {
+			fmt.Printf("⚡ Crawl Speed: %.1f pages/sec\n", audit.Crawl.PagesPerSecond)
+		}
+		if audit.Crawl != nil && audit.Crawl.NotCrawled > 0 {
+			fmt.Printf("⚠️  Not Crawled: %d discovered URLs (page limit reached)\n", audit.Crawl.NotCrawled)
+		}
+		if audit.Crawl != nil && audit.Crawl.CrawlDelay > 0 {
+			fmt.Printf("🐢 Crawl-delay: %s (robots.txt)\n", audit.Crawl.CrawlDelay)
+		}
+		if audit.Crawl != nil && audit.Crawl.Throttled > 0 {
+			fmt.Printf("🚦 Throttled: %d responses (429/503), retried after backoff\n", audit.Crawl.Throttled)
+		}
+		fmt.Printf("\n")
 
 		if len(audit.Pages) > 0 {
 			fmt.Printf("📄 Pages:\n")
@@ -179,6 +382,35 @@ var auditShowCmd = &cobra.Command{
 			}
 		}
 
+		if audit.Crawl != nil && len(audit.Crawl.Skipped) > 0 {
+			fmt.Printf("\n🚫 Not Audited:\n")
+			fmt.Printf("─────────────────────────────────────────────────────\n")
+			for _, reason := range []struct{ key, label string }{
+				{crawler.SkipIgnored, "Ignored by exclude patterns"},
+				{crawler.SkipOutOfScope, "Out of scope (base path or include patterns)"},
+				{crawler.SkipRobots, "Blocked by robots.txt"},
+				{crawler.SkipDepth, "Beyond the maximum depth"},
+			} {
+				if urls := audit.Crawl.Skipped[reason.key]; len(urls) > 0 {
+					fmt.Printf("  %s: %d\n", reason.label, len(urls))
+					printURLList(urls, 5)
+				}
+			}
+		}
+
+		if audit.Sitemap != nil {
+			fmt.Printf("\n🗺️  Sitemap Coverage (%d URLs in sitemap):\n", audit.Sitemap.SitemapURLs)
+			fmt.Printf("─────────────────────────────────────────────────────\n")
+			if audit.Sitemap.SitemapURLs == 0 {
+				fmt.Printf("  No sitemap found (checked robots.txt and /sitemap.xml)\n")
+			} else {
+				fmt.Printf("  In sitemap but never linked: %d\n", len(audit.Sitemap.Unlinked))
+				printURLList(audit.Sitemap.Unlinked, 5)
+				fmt.Printf("  Linked but missing from sitemap: %d\n", len(audit.Sitemap.NotInSitemap))
+				printURLList(audit.Sitemap.NotInSitemap, 5)
+			}
+		}
+
 		if audit.Summary != nil && len(audit.Summary.Recommendations) > 0 {
 			fmt.Printf("\n💡 Recommendations:\n")
 			fmt.Printf("─────────────────────────────────────────────────────\n")
@@ -226,18 +458,241 @@ var auditExportCmd = &cobra.Command{
 	},
 }
 
+// printAuditComplete prints the outcome of a finished audit
+func printAuditComplete(result *services.AuditResult) {
+	overallScore := "N/A"
+	if result.OverallScore != nil {
+		overallScore = fmt.Sprintf("%.1f/100", *result.OverallScore)
+	}
+
+	if result.Status == "partial" {
+		log.Info("Audit interrupted, partial results saved",
+			"audit_id", result.ID,
+			"pages_analyzed", result.PagesAnalyzed,
+			"overall_score", overallScore)
+
+		fmt.Printf("\n⏸️  Audit interrupted - partial results saved\n")
+	} else {
+		log.Info("Audit completed successfully",
+			"audit_id", result.ID,
+			"pages_analyzed", result.PagesAnalyzed,
+			"overall_score", overallScore)
+
+		fmt.Printf("\n✅ Audit complete!\n")
+	}
+	fmt.Printf("   ID: %s\n", result.ID)
+	fmt.Printf("   Pages analyzed: %d\n", result.PagesAnalyzed)
+	fmt.Printf("   Overall score: %s\n", overallScore)
+	if result.Crawl != nil && result.Crawl.PagesPerSecond > 0 {
+		fmt.Printf("   Crawl speed: %.1f pages/sec (%s)\n", result.Crawl.PagesPerSecond, result.Crawl.Duration.Round(time.Second))
+	}
+	if result.Crawl != nil && result.Crawl.NotCrawled > 0 {
+		fmt.Printf("   ⚠️  Page limit reached: %d discovered URLs were not crawled (raise --max-pages)\n", result.Crawl.NotCrawled)
+	}
+	if result.Crawl != nil && result.Crawl.CrawlDelay > 0 {
+		fmt.Printf("   🐢 Honored robots.txt Crawl-delay of %s\n", result.Crawl.CrawlDelay)
+	}
+	if result.Crawl != nil && result.Crawl.Throttled > 0 {
+		fmt.Printf("   🚦 Server throttled %d requests (429/503); the crawler backed off\n", result.Crawl.Throttled)
+	}
+	fmt.Printf("\nView details: seo audit show %s\n", result.ID)
+	if result.Status == "partial" {
+		fmt.Printf("Continue later: seo audit resume %s\n", result.ID[:8])
+	}
+}
+
+// interruptContext returns a context cancelled by the first Ctrl-C or SIGTERM, so the
+// audit can stop crawling and save what it has. Later signals get the default
+// behavior: a second Ctrl-C quits immediately.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case <-signals:
+			fmt.Fprintf(os.Stderr, "\n⏸️  Interrupted - analyzing pages already fetched (press Ctrl-C again to quit)\n")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// addAuthFlags registers the authentication flags read by authConfigFromFlags
+func addAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("header", "H", nil, "Extra HTTP header sent with requests to the audited host, e.g. \"Authorization: Bearer xyz\" (repeatable)")
+	cmd.Flags().String("basic-auth", "", "HTTP basic auth credentials as user:password")
+	cmd.Flags().String("cookies", "", "Cookie file to load (JSON export or Netscape cookies.txt)")
+	cmd.Flags().String("storage-state", "", "Playwright storageState JSON file with a logged-in session")
+	cmd.Flags().String("login-script", "", "YAML/JSON file of login steps run in the browser before crawling")
+}
+
+// authConfigFromFlags merges the auth section of the config file with the auth flags.
+// Flags take precedence; headers from both sources are combined.
+func authConfigFromFlags(cmd *cobra.Command, cfg *config.Config) (services.AuthConfig, error) {
+	auth := services.AuthConfig{
+		Headers:      make(map[string]string),
+		BasicAuth:    cfg.Auth.BasicAuth,
+		CookieFile:   cfg.Auth.CookieFile,
+		StorageState: cfg.Auth.StorageState,
+		LoginScript:  cfg.Auth.LoginScript,
+	}
+	for name, value := range cfg.Auth.Headers {
+		auth.Headers[name] = value
+	}
+
+	headers, _ := cmd.Flags().GetStringArray("header")
+	for _, header := range headers {
+		name, value, err := crawler.ParseHeader(header)
+		if err != nil {
+			return auth, err
+		}
+		auth.Headers[name] = value
+	}
+
+	if value, _ := cmd.Flags().GetString("basic-auth"); value != "" {
+		auth.BasicAuth = value
+	}
+	if value, _ := cmd.Flags().GetString("cookies"); value != "" {
+		auth.CookieFile = value
+	}
+	if value, _ := cmd.Flags().GetString("storage-state"); value != "" {
+		auth.StorageState = value
+	}
+	if value, _ := cmd.Flags().GetString("login-script"); value != "" {
+		auth.LoginScript = value
+	}
+
+	return auth, nil
+}
+
+// budgetFromFlags merges the budgets section of the config file with the --budget flag.
+// Flags take precedence; limits set in neither place use the defaults.
+func budgetFromFlags(cmd *cobra.Command, cfg *config.Config) (audit.PerformanceBudget, error) {
+	budget := audit.PerformanceBudget{
+		TTFBMs:     cfg.Budgets.TTFBMs,
+		LCPMs:      cfg.Budgets.LCPMs,
+		CLS:        cfg.Budgets.CLS,
+		TransferKB: cfg.Budgets.TransferKB,
+		Requests:   cfg.Budgets.Requests,
+	}
+
+	entries, _ := cmd.Flags().GetStringSlice("budget")
+	for _, entry := range entries {
+		if err := budget.Set(entry); err != nil {
+			return budget, err
+		}
+	}
+
+	return budget.WithDefaults(), nil
+}
+
+// patternsFromFlags merges the include and exclude patterns of the config file with
+// the flags. Include flags replace the configured includes; excludes are combined.
+func patternsFromFlags(cmd *cobra.Command, cfg *config.Config) ([]string, []string, error) {
+	include := cfg.IncludePatterns
+	if cmd.Flags().Changed("include") {
+		include, _ = cmd.Flags().GetStringSlice("include")
+	}
+
+	ignore, _ := cmd.Flags().GetStringSlice("ignore")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+	exclude = append(append(append([]string(nil), ignore...), exclude...), cfg.ExcludePatterns...)
+
+	// Reject invalid regexes before the audit starts
+	if _, err := crawler.CompilePatterns(include); err != nil {
+		return nil, nil, err
+	}
+	if _, err := crawler.CompilePatterns(exclude); err != nil {
+		return nil, nil, err
+	}
+
+	return include, exclude, nil
+}
+
+// urlPolicyFromFlags merges the url_normalization section of the config file with
+// the URL normalization flags. Strip lists from both sources are combined.
+func urlPolicyFromFlags(cmd *cobra.Command, cfg *config.Config) (urlnorm.Policy, error) {
+	urls := cfg.URLNormalization
+	policy := urlnorm.Policy{
+		StripParams:     append([]string(nil), urls.StripParams...),
+		KeepTracking:    urls.KeepTracking,
+		KeepQueryOrder:  urls.KeepQueryOrder,
+		CaseInsensitive: urls.CaseInsensitive,
+		FoldIndex:       urls.FoldIndex,
+	}
+
+	params, _ := cmd.Flags().GetStringSlice("strip-params")
+	for _, param := range params {
+		param = strings.TrimSpace(param)
+		if param == "" || param == "*" {
+			return policy, fmt.Errorf("invalid query parameter %q", param)
+		}
+		policy.StripParams = append(policy.StripParams, param)
+	}
+	if value, _ := cmd.Flags().GetBool("case-insensitive-paths"); value {
+		policy.CaseInsensitive = true
+	}
+	if value, _ := cmd.Flags().GetBool("fold-index"); value {
+		policy.FoldIndex = true
+	}
+
+	return policy, nil
+}
+
+// printURLList prints up to limit URLs as an indented list
+func printURLList(urls []string, limit int) {
+	for i, u := range urls {
+		if i >= limit {
+			fmt.Printf("      ... and %d more\n", len(urls)-limit)
+			break
+		}
+		fmt.Printf("      - %s\n", u)
+	}
+}
+
 func init() {
 	// Add flags to run command
 	auditRunCmd.Flags().IntP("port", "p", 3000, "Port for localhost audit")
+	auditRunCmd.Flags().StringP("url", "u", "", "Base URL to audit, e.g. https://staging.example.com/docs (overrides --port)")
+	auditRunCmd.Flags().Bool("insecure", false, "Accept invalid TLS certificates (self-signed dev certificates)")
+	addAuthFlags(auditRunCmd)
 	auditRunCmd.Flags().IntP("concurrency", "c", 4, "Number of concurrent requests")
 	auditRunCmd.Flags().IntP("max-pages", "m", 0, "Maximum pages to audit (0 = unlimited)")
 	auditRunCmd.Flags().IntP("max-depth", "d", 0, "Maximum crawl depth (0 = unlimited)")
 	auditRunCmd.Flags().StringSliceP("ignore", "i", []string{"/api", "/admin"}, "URL patterns to ignore")
+	auditRunCmd.Flags().StringSlice("include", nil, "Only audit URLs matching these patterns: globs (/blog/**), /regex/ or substrings")
+	auditRunCmd.Flags().StringSlice("exclude", nil, "Never audit URLs matching these patterns, on top of --ignore")
+	auditRunCmd.Flags().String("urls-file", "", "Audit only the URLs or paths listed in this file, one per line (- reads stdin); links are not followed")
+	auditRunCmd.Flags().String("renderer", "browser", "Page renderer: browser (Playwright, runs JavaScript) or http (plain HTTP, no browser needed)")
+	auditRunCmd.Flags().StringSlice("block-resources", nil, "Resource types the browser skips loading, e.g. image,font,media (faster when only the DOM matters)")
+	auditRunCmd.Flags().String("profile", "", "Crawl as "+strings.Join(crawler.ProfileNames(), ", ")+" (sets User-Agent, viewport and robots.txt group)")
+	auditRunCmd.Flags().String("device", "", "Emulate mobile, desktop or both (both renders each page twice and checks mobile/desktop parity)")
+	auditRunCmd.Flags().Bool("ignore-robots-errors", false, "Crawl without restrictions when robots.txt is unreachable or returns 5xx, instead of crawling nothing")
+	auditRunCmd.Flags().String("robots-agent", "", "User-agent whose robots.txt rules the crawl obeys (default: the profile's, or "+crawler.DefaultRobotsAgent+")")
+	auditRunCmd.Flags().StringSlice("strip-params", nil, "Query parameters ignored when comparing URLs, on top of tracking parameters (a trailing * matches a prefix)")
+	auditRunCmd.Flags().Bool("case-insensitive-paths", false, "Treat URL paths differing only in case as the same page")
+	auditRunCmd.Flags().Bool("fold-index", false, "Treat /dir/index.html (and index.htm, index.php) as /dir")
+	auditRunCmd.Flags().Bool("compare-raw", false, "Also fetch each page's raw HTML and flag titles, canonicals, links and content that need JavaScript (browser renderer)")
+	auditRunCmd.Flags().Bool("screenshots", false, "Save full-page and above-the-fold PNGs of each page in the audit directory (browser renderer)")
+	auditRunCmd.Flags().StringSlice("budget", nil, "Performance budget as key=value: ttfb, lcp, cls, transfer, requests (e.g. lcp=2.5s,transfer=2MB)")
+	auditRunCmd.Flags().String("exec", "", "Dev server command to start before the audit and stop afterwards, e.g. \"npm run dev\"")
+	auditRunCmd.Flags().String("wait-for", "", "URL polled until the --exec server responds (default: the audited URL)")
+	auditRunCmd.Flags().Duration("wait-timeout", 60*time.Second, "How long to wait for the --exec server to respond")
+	auditRunCmd.Flags().Float64("rate", 0, "Maximum requests per second per host (0 = unlimited); robots.txt Crawl-delay is always honored")
+
+	addAuthFlags(auditResumeCmd)
 
 	// Add subcommands
 	auditCmd.AddCommand(auditRunCmd)
 	auditCmd.AddCommand(auditListCmd)
 	auditCmd.AddCommand(auditShowCmd)
+	auditCmd.AddCommand(auditResumeCmd)
 	auditCmd.AddCommand(auditExportCmd)
 
 	// Add to root command
@@ -3,8 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/ugolbck/seofordev/internal/audit"
 	"github.com/ugolbck/seofordev/internal/config"
 )
 
@@ -42,6 +45,44 @@ Examples:
 			fmt.Printf("  Max Depth: unlimited\n")
 		}
 		fmt.Printf("  Ignore Patterns: %v\n", cfg.DefaultIgnorePatterns)
+		if len(cfg.IncludePatterns) > 0 {
+			fmt.Printf("  Include Patterns: %v\n", cfg.IncludePatterns)
+		}
+		if len(cfg.ExcludePatterns) > 0 {
+			fmt.Printf("  Exclude Patterns: %v\n", cfg.ExcludePatterns)
+		}
+
+		// Authentication (secrets are never printed)
+		fmt.Printf("\n🔐 Authentication:\n")
+		for _, line := range authSummary(cfg.Auth) {
+			fmt.Printf("  %s\n", line)
+		}
+
+		// Performance budget (defaults fill unset limits)
+		budget := audit.PerformanceBudget{
+			TTFBMs:     cfg.Budgets.TTFBMs,
+			LCPMs:      cfg.Budgets.LCPMs,
+			CLS:        cfg.Budgets.CLS,
+			TransferKB: cfg.Budgets.TransferKB,
+			Requests:   cfg.Budgets.Requests,
+		}.WithDefaults()
+		fmt.Printf("\n⏱️  Performance Budget:\n")
+		fmt.Printf("  TTFB: %dms\n", *budget.TTFBMs)
+		fmt.Printf("  LCP: %dms\n", *budget.LCPMs)
+		fmt.Printf("  CLS: %.2f\n", *budget.CLS)
+		fmt.Printf("  Transfer: %d KB\n", *budget.TransferKB)
+		fmt.Printf("  Requests: %d\n", *budget.Requests)
+
+		// URL canonicalization
+		urls := cfg.URLNormalization
+		fmt.Printf("\n🔗 URL Normalization:\n")
+		fmt.Printf("  Strip Tracking Params: %t\n", !urls.KeepTracking)
+		if len(urls.StripParams) > 0 {
+			fmt.Printf("  Strip Params: %v\n", urls.StripParams)
+		}
+		fmt.Printf("  Sort Query: %t\n", !urls.KeepQueryOrder)
+		fmt.Printf("  Case-Insensitive Paths: %t\n", urls.CaseInsensitive)
+		fmt.Printf("  Fold Index Files: %t\n", urls.FoldIndex)
 
 		// Configuration file location
 		homeDir, _ := os.UserHomeDir()
@@ -50,6 +91,39 @@ Examples:
 	},
 }
 
+// authSummary describes the configured authentication with header values and
+// passwords masked
+func authSummary(auth config.AuthConfig) []string {
+	if len(auth.Headers) == 0 && auth.BasicAuth == "" && auth.CookieFile == "" && auth.StorageState == "" && auth.LoginScript == "" {
+		return []string{"None configured"}
+	}
+
+	var lines []string
+	names := make([]string, 0, len(auth.Headers))
+	for name := range auth.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("Header: %s: ****", name))
+	}
+	if user, _, ok := strings.Cut(auth.BasicAuth, ":"); ok {
+		lines = append(lines, fmt.Sprintf("Basic Auth: %s:****", user))
+	} else if auth.BasicAuth != "" {
+		lines = append(lines, "Basic Auth: ****") // Malformed, and maybe a bare password
+	}
+	if auth.CookieFile != "" {
+		lines = append(lines, "Cookie File: "+auth.CookieFile)
+	}
+	if auth.StorageState != "" {
+		lines = append(lines, "Storage State: "+auth.StorageState)
+	}
+	if auth.LoginScript != "" {
+		lines = append(lines, "Login Script: "+auth.LoginScript)
+	}
+	return lines
+}
+
 func init() {
 	// Add to root command
 	rootCmd.AddCommand(configCmd)
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ugolbck/seofordev/internal/config"
+)
+
+func TestAuthSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		auth config.AuthConfig
+		want []string
+	}{
+		{name: "nothing", want: []string{"None configured"}},
+		{
+			name: "headers sorted and masked",
+			auth: config.AuthConfig{Headers: map[string]string{"X-Token": "secret", "Authorization": "Bearer secret"}},
+			want: []string{"Header: Authorization: ****", "Header: X-Token: ****"},
+		},
+		{
+			name: "basic auth password masked",
+			auth: config.AuthConfig{BasicAuth: "admin:secret:with:colons"},
+			want: []string{"Basic Auth: admin:****"},
+		},
+		{
+			name: "malformed basic auth masked",
+			auth: config.AuthConfig{BasicAuth: "secret"},
+			want: []string{"Basic Auth: ****"},
+		},
+		{
+			name: "files shown",
+			auth: config.AuthConfig{CookieFile: "cookies.txt", StorageState: "state.json", LoginScript: "login.yml"},
+			want: []string{"Cookie File: cookies.txt", "Storage State: state.json", "Login Script: login.yml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := authSummary(tt.auth)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("authSummary() = %q, want %q", got, tt.want)
+			}
+			for _, line := range got {
+				if strings.Contains(line, "secret") {
+					t.Errorf("authSummary() leaks a secret: %q", line)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ugolbck/seofordev/internal/events"
+)
+
+// progressRedraw limits how often the live progress line is redrawn
+const progressRedraw = 100 * time.Millisecond
+
+// auditProgress renders a live one-line progress display from audit events.
+// Nothing is drawn when stderr is not a terminal.
+type auditProgress struct {
+	mu         sync.Mutex
+	out        io.Writer
+	live       bool
+	started    time.Time
+	lastDraw   time.Time
+	lastURL    string
+	discovered int
+	fetched    int
+	analyzed   int
+	failed     int
+}
+
+func newAuditProgress() *auditProgress {
+	return &auditProgress{
+		out:     os.Stderr,
+		live:    isTerminal(os.Stderr),
+		started: time.Now(),
+	}
+}
+
+// handle updates the counters for an event; it is used as the audit service's event handler
+func (p *auditProgress) handle(event events.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch event.Type {
+	case events.PageDiscovered:
+		p.discovered++
+	case events.PageFetched:
+		p.fetched++
+		p.lastURL = event.URL
+	case events.PageAnalyzed:
+		p.analyzed++
+	case events.PageFailed:
+		p.failed++
+	}
+
+	if time.Since(p.lastDraw) >= progressRedraw || event.Type == events.AuditCompleted {
+		p.draw()
+	}
+}
+
+// finish draws the final counts and moves to a new line
+func (p *auditProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.live {
+		return
+	}
+	p.lastURL = ""
+	p.draw()
+	fmt.Fprintln(p.out)
+}
+
+func (p *auditProgress) draw() {
+	if !p.live {
+		return
+	}
+	p.lastDraw = time.Now()
+
+	line := fmt.Sprintf("⏳ %s  %d discovered · %d fetched · %d analyzed · %d failed",
+		time.Since(p.started).Round(time.Second), p.discovered, p.fetched, p.analyzed, p.failed)
+	if p.lastURL != "" {
+		line += "  " + truncateURL(p.lastURL, 50)
+	}
+
+	// Return to the start of the line and clear it before redrawing
+	fmt.Fprintf(p.out, "\r\033[K%s", line)
+}
+
+// truncateURL shortens a URL to at most limit characters, keeping its end
+func truncateURL(u string, limit int) string {
+	runes := []rune(u)
+	if len(runes) <= limit {
+		return u
+	}
+	return "…" + string(runes[len(runes)-limit+1:])
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
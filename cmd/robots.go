@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/ugolbck/seofordev/internal/crawler"
+	"github.com/ugolbck/seofordev/internal/robots"
+)
+
+var robotsCmd = &cobra.Command{
+	Use:   "robots",
+	Short: "Inspect robots.txt rules",
+}
+
+var robotsTestCmd = &cobra.Command{
+	Use:   "test <url>",
+	Short: "Explain whether robots.txt lets a crawler fetch a URL",
+	Long: `Fetch the site's robots.txt and explain which group and rule allow or block a URL.
+Rules are evaluated as described in RFC 9309: groups for the same user-agent are merged,
+the longest matching pattern wins, and Allow wins a tie.
+
+Examples:
+  seo robots test http://localhost:3000/admin/login
+  seo robots test https://staging.example.com/blog --ua Bingbot
+  seo robots test https://example.com/search?q=shoes --ua GPTBot`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := args[0]
+		userAgent, _ := cmd.Flags().GetString("ua")
+		insecure, _ := cmd.Flags().GetBool("insecure")
+
+		// Crawl profile names stand for the robots.txt group they obey
+		if profile, err := crawler.LookupProfile(userAgent); err == nil {
+			userAgent = profile.RobotsAgent
+		}
+
+		parsed, err := url.Parse(target)
+		if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			return fmt.Errorf("❌ invalid URL %q: include the scheme, e.g. https://example.com/page", target)
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if insecure {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		client := &http.Client{Timeout: 10 * time.Second, Transport: transport}
+
+		rules, err := robots.Fetch(context.Background(), client, target)
+		if rules == nil {
+			return fmt.Errorf("❌ failed to fetch robots.txt: %v", err)
+		}
+
+		status := fmt.Sprintf("%d", rules.Status)
+		if err != nil && rules.Status == 0 {
+			status = err.Error()
+		}
+		fmt.Printf("🤖 %s (%s)\n", rules.URL, status)
+		fmt.Printf("   User-agent: %s (group %q)\n", userAgent, robots.ProductToken(userAgent))
+
+		verdict := rules.Test(userAgent, target)
+		if len(verdict.Agents) > 0 {
+			fmt.Printf("   Matched group: User-agent: %s\n", strings.Join(verdict.Agents, ", "))
+		}
+		if verdict.Rule != nil {
+			fmt.Printf("   Matched rule:  %s (line %d)\n", verdict.Rule, verdict.Rule.Line)
+		}
+		if delay := rules.CrawlDelay(userAgent); delay > 0 {
+			fmt.Printf("   Crawl-delay:   %s\n", delay)
+		}
+		fmt.Println()
+
+		if verdict.Allowed {
+			fmt.Printf("✅ Allowed: %s\n", verdict.Path)
+		} else {
+			fmt.Printf("⛔ Blocked: %s\n", verdict.Path)
+		}
+		fmt.Printf("   %s\n", verdict.Reason)
+		return nil
+	},
+}
+
+func init() {
+	robotsTestCmd.Flags().String("ua", crawler.DefaultRobotsAgent, "User-agent or crawl profile to evaluate, e.g. Googlebot, Bingbot, GPTBot, googlebot-smartphone")
+	robotsTestCmd.Flags().Bool("insecure", false, "Accept invalid TLS certificates (self-signed dev certificates)")
+
+	robotsCmd.AddCommand(robotsTestCmd)
+	rootCmd.AddCommand(robotsCmd)
+}
@@ -69,6 +69,7 @@ Use the available commands below to get started, or run 'seo <command> --help' f
 		fmt.Printf("Examples:\n")
 		fmt.Printf("  seo audit run                        # Audit localhost:3000\n")
 		fmt.Printf("  seo audit run --port 8080            # Audit localhost:8080\n")
+		fmt.Printf("  seo audit run --url http://web:8080  # Audit any base URL\n")
 		fmt.Printf("  seo config                           # Show configuration\n\n")
 	},
 }
@@ -1,9 +1,11 @@
 package audit
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -125,6 +127,9 @@ func (a *Analyzer) AnalyzeContent(htmlContent string, pageURL string) (*Analysis
 		Meta:      make(map[string]interface{}),
 	}
 
+	// Schema types are read first: extractContent removes scripts, JSON-LD included
+	schemaTypes := extractSchemaTypes(doc)
+
 	// Extract all SEO elements
 	a.extractMetaData(doc, result)
 	a.extractHeadings(doc, result)
@@ -132,6 +137,7 @@ func (a *Analyzer) AnalyzeContent(htmlContent string, pageURL string) (*Analysis
 	a.extractLinks(doc, pageURL, result)
 	a.extractImages(doc, result)
 	a.extractTechnicalSEO(doc, result)
+	result.Schema.Types = schemaTypes
 
 	return result, nil
 }
@@ -350,6 +356,61 @@ func (a *Analyzer) extractTechnicalSEO(doc *goquery.Document, result *AnalysisRe
 	}
 }
 
+// extractSchemaTypes collects the sorted, distinct types declared in JSON-LD and microdata
+func extractSchemaTypes(doc *goquery.Document) []string {
+	types := make(map[string]bool)
+
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var data interface{}
+		if err := json.Unmarshal([]byte(s.Text()), &data); err == nil {
+			collectJSONLDTypes(data, types)
+		}
+	})
+
+	doc.Find(`[itemtype]`).Each(func(i int, s *goquery.Selection) {
+		itemType, _ := s.Attr("itemtype")
+		for _, t := range strings.Fields(itemType) {
+			types[t[strings.LastIndex(t, "/")+1:]] = true
+		}
+	})
+
+	if len(types) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(types))
+	for t := range types {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectJSONLDTypes walks a JSON-LD value, including @graph, and records every @type
+func collectJSONLDTypes(value interface{}, types map[string]bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			collectJSONLDTypes(item, types)
+		}
+	case map[string]interface{}:
+		switch t := v["@type"].(type) {
+		case string:
+			types[t] = true
+		case []interface{}:
+			for _, item := range t {
+				if name, ok := item.(string); ok {
+					types[name] = true
+				}
+			}
+		}
+		for key, item := range v {
+			if key != "@type" {
+				collectJSONLDTypes(item, types)
+			}
+		}
+	}
+}
+
 // extractRobotsMeta extracts robots meta directives
 func (a *Analyzer) extractRobotsMeta(doc *goquery.Document) *RobotsData {
 	robots := &RobotsData{}
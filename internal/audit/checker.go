@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"regexp"
 	"strings"
+
+	"github.com/ugolbck/seofordev/internal/crawler"
 )
 
 // CheckResult represents the result of an individual SEO check
@@ -23,48 +26,82 @@ type CheckResults struct {
 	Checks             map[string]CheckResult `json:"checks"`
 }
 
+// checkWeights holds the importance of each check, used to compute page scores
+var checkWeights = map[string]int{
+	"response_status_code":        95,
+	"title_presence":              85,
+	"title_length":                75,
+	"unique_title_tag":            70,
+	"meta_description_presence":   80,
+	"meta_description_length":     65,
+	"unique_meta_description":     60,
+	"h1_presence":                 90,
+	"unique_h1_heading":           85,
+	"h1_length":                   70,
+	"h2_presence":                 50,
+	"content_length":              75,
+	"canonical_url_presence":      55,
+	"url_matches_canonical":       50,
+	"unique_canonical_link":       45,
+	"meta_robots_indexing":        65,
+	"outlinks_count":              35,
+	"external_links_count":        30,
+	"missing_alt_attribute":       55,
+	"meta_refresh_redirect":       25,
+	"viewport_meta":               40,
+	"charset_declared":            35,
+	"images_optimization":         45,
+	"structured_data":             35,
+	"page_loading_speed":          60,
+	"social_media_meta":           25,
+	"redirect_chain_length":       50,
+	"temporary_redirect":          40,
+	"redirect_loop":               90,
+	"internal_links_to_redirects": 35,
+	"response_compression":        40,
+	"response_caching":            30,
+	"hsts_header":                 25,
+	"javascript_errors":           60,
+	"console_errors":              35,
+	"failed_requests":             45,
+	"horizontal_overflow":         45,
+	"content_parity":              60,
+	"link_parity":                 45,
+	"title_parity":                50,
+	"structured_data_parity":      40,
+	"js_title":                    55,
+	"js_meta_description":         35,
+	"js_canonical":                55,
+	"js_robots_meta":              70,
+	"js_h1":                       40,
+	"js_internal_links":           50,
+	"js_word_count":               50,
+}
+
+// linkCanonicalPattern matches a rel=canonical entry in an HTTP Link header
+var linkCanonicalPattern = regexp.MustCompile(`(?i)<([^>]*)>\s*;[^,]*\brel="?canonical"?`)
+
 // Checker performs comprehensive SEO checks on analyzed page data
 type Checker struct {
 	analysis   *AnalysisResult
+	page       *crawler.PageResult
 	statusCode int
 	results    map[string]CheckResult
 	weights    map[string]int
+	renders    []deviceAnalysis // The page rendered on other devices, for parity checks
+	raw        *AnalysisResult  // The page's raw HTML, before JavaScript ran
+	budget     PerformanceBudget
 }
 
 // NewChecker creates a new SEO checker
-func NewChecker(analysis *AnalysisResult, statusCode int) *Checker {
+func NewChecker(analysis *AnalysisResult, page *crawler.PageResult) *Checker {
 	return &Checker{
 		analysis:   analysis,
-		statusCode: statusCode,
+		page:       page,
+		statusCode: page.StatusCode,
 		results:    make(map[string]CheckResult),
-		weights: map[string]int{
-			"response_status_code":      95,
-			"title_presence":            85,
-			"title_length":              75,
-			"unique_title_tag":          70,
-			"meta_description_presence": 80,
-			"meta_description_length":   65,
-			"unique_meta_description":   60,
-			"h1_presence":               90,
-			"unique_h1_heading":         85,
-			"h1_length":                 70,
-			"h2_presence":               50,
-			"content_length":            75,
-			"canonical_url_presence":    55,
-			"url_matches_canonical":     50,
-			"unique_canonical_link":     45,
-			"meta_robots_indexing":      65,
-			"outlinks_count":            35,
-			"external_links_count":      30,
-			"missing_alt_attribute":     55,
-			"meta_refresh_redirect":     25,
-			"viewport_meta":             40,
-			"charset_declared":          35,
-			"images_optimization":       45,
-			"structured_data":           35,
-			"page_loading_speed":        60,
-			"social_media_meta":         25,
-		},
+		weights:    checkWeights,
+		budget:     DefaultBudget(),
 	}
 }
 
@@ -105,8 +142,36 @@ func (c *Checker) RunAllChecks() *CheckResults {
 	c.checkCharsetDeclared()
 	c.checkImagesOptimization()
 	c.checkStructuredData()
-	c.checkPageLoadingSpeed()
 	c.checkSocialMediaMeta()
+	c.checkRedirectChainLength()
+	c.checkRedirectLoop()
+
+	// Header-level checks need the HTTP response headers
+	if len(c.page.Headers) > 0 {
+		c.checkResponseCompression()
+		c.checkResponseCaching()
+		c.checkHSTSHeader()
+	}
+
+	// Loading speed is only known from the browser's own measurements
+	if c.page.Performance != nil {
+		c.checkPageLoadingSpeed()
+	}
+
+	// Runtime checks need the browser renderer's diagnostics
+	if c.page.Diagnostics != nil {
+		c.checkJavaScriptErrors()
+		c.checkConsoleErrors()
+		c.checkFailedRequests()
+	}
+
+	// Device emulation: mobile layout and parity between renders
+	c.runParityChecks()
+
+	// Content that depends on JavaScript, found by comparing with the raw HTML
+	if c.raw != nil {
+		c.runRawHTMLChecks()
+	}
 
 	// Calculate overall score
 	score := c.calculateScore()
@@ -119,6 +184,14 @@ func (c *Checker) RunAllChecks() *CheckResults {
 	}
 }
 
+// RunFailedPageChecks runs the checks that still apply to pages that could not be loaded
+func (c *Checker) RunFailedPageChecks() map[string]CheckResult {
+	if c.page.RedirectLoop {
+		c.checkRedirectLoop()
+	}
+	return c.results
+}
+
 // checkIndexability determines if the page is indexable by search engines
 func (c *Checker) checkIndexability() bool {
 	if c.statusCode != 200 {
@@ -129,6 +202,10 @@ func (c *Checker) checkIndexability() bool {
 		return false
 	}
 
+	if c.headerNoIndex() {
+		return false
+	}
+
 	return true
 }
 
@@ -142,9 +219,52 @@ func (c *Checker) getIndexabilityReason() string {
 		return "Meta robots noindex directive"
 	}
 
+	if c.headerNoIndex() {
+		return "X-Robots-Tag noindex header"
+	}
+
 	return ""
 }
 
+// header returns a response header value (header names are stored lowercase)
+func (c *Checker) header(name string) string {
+	return c.page.Headers[strings.ToLower(name)]
+}
+
+// headerNoIndex reports whether X-Robots-Tag contains a noindex directive.
+// Directives may be scoped to a crawler ("googlebot: noindex"), which still applies.
+func (c *Checker) headerNoIndex() bool {
+	for _, line := range strings.Split(c.header("X-Robots-Tag"), "\n") {
+		for _, directive := range strings.Split(line, ",") {
+			directive = strings.ToLower(strings.TrimSpace(directive))
+			if i := strings.LastIndex(directive, ":"); i >= 0 {
+				directive = strings.TrimSpace(directive[i+1:])
+			}
+			if directive == "noindex" || directive == "none" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headerCanonical returns the canonical URL declared in the HTTP Link header, if any
+func (c *Checker) headerCanonical() string {
+	match := linkCanonicalPattern.FindStringSubmatch(c.header("Link"))
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(match[1])
+}
+
+// canonical returns the canonical URL from the HTML, falling back to the Link header
+func (c *Checker) canonical() string {
+	if c.analysis.Technical.Canonical != "" {
+		return c.analysis.Technical.Canonical
+	}
+	return c.headerCanonical()
+}
+
 // Individual check methods
 
 func (c *Checker) checkResponseStatusCode() {
@@ -362,22 +482,25 @@ func (c *Checker) checkContentLength() {
 }
 
 func (c *Checker) checkCanonicalURLPresence() {
-	hasCanonical := c.analysis.Technical.Canonical != ""
+	canonical := c.canonical()
+	hasCanonical := canonical != ""
 	message := "Page has a canonical URL"
 	if !hasCanonical {
 		message = "Page is missing a canonical URL"
+	} else if c.analysis.Technical.Canonical == "" {
+		message = "Page has a canonical URL (HTTP Link header)"
 	}
 
 	c.results["canonical_url_presence"] = CheckResult{
 		Passed:  hasCanonical,
-		Value:   c.analysis.Technical.Canonical,
+		Value:   canonical,
 		Message: message,
 		Weight:  c.weights["canonical_url_presence"],
 	}
 }
 
 func (c *Checker) checkURLMatchesCanonical() {
-	canonical := c.analysis.Technical.Canonical
+	canonical := c.canonical()
 	if canonical == "" {
 		c.results["url_matches_canonical"] = CheckResult{
 			Passed:  false,
@@ -388,9 +511,12 @@ func (c *Checker) checkURLMatchesCanonical() {
 		return
 	}
 
-	// Parse URLs for comparison
+	// Parse URLs for comparison (relative canonicals resolve against the page)
 	pageURL, err1 := url.Parse(c.analysis.URL)
 	canonicalURL, err2 := url.Parse(canonical)
+	if err1 == nil && err2 == nil {
+		canonicalURL = pageURL.ResolveReference(canonicalURL)
+	}
 
 	passed := false
 	message := "Canonical URL does not match page URL"
@@ -405,6 +531,17 @@ func (c *Checker) checkURLMatchesCanonical() {
 		}
 	}
 
+	// HTML and Link header canonicals must agree
+	if headerCanonical := c.headerCanonical(); passed && headerCanonical != "" && c.analysis.Technical.Canonical != "" {
+		if headerURL, err := url.Parse(headerCanonical); err == nil && pageURL != nil {
+			headerNorm := strings.TrimSuffix(pageURL.ResolveReference(headerURL).String(), "/")
+			if headerNorm != strings.TrimSuffix(canonicalURL.String(), "/") {
+				passed = false
+				message = fmt.Sprintf("Link header canonical (%s) conflicts with HTML canonical", headerCanonical)
+			}
+		}
+	}
+
 	c.results["url_matches_canonical"] = CheckResult{
 		Passed:  passed,
 		Value:   canonical,
@@ -531,10 +668,13 @@ func (c *Checker) checkViewportMeta() {
 }
 
 func (c *Checker) checkCharsetDeclared() {
-	hasCharset := c.analysis.Technical.CharsetDeclared
+	headerCharset := strings.Contains(strings.ToLower(c.header("Content-Type")), "charset=")
+	hasCharset := c.analysis.Technical.CharsetDeclared || headerCharset
 	message := "Page declares charset"
 	if !hasCharset {
-		message = "Page is missing charset declaration"
+		message = "Page is missing charset declaration (meta tag or Content-Type header)"
+	} else if !c.analysis.Technical.CharsetDeclared {
+		message = "Page declares charset in the Content-Type header"
 	}
 
 	c.results["charset_declared"] = CheckResult{
@@ -556,7 +696,7 @@ func (c *Checker) checkImagesOptimization() {
 		altRatio := float64(withAlt) / float64(totalImages)
 		passed = altRatio >= 0.8 // 80% of images should have alt text
 		if !passed {
-			message = fmt.Sprintf("Only %.0f%% of images have alt text (aim for 80%+)", altRatio*100)
+			message = fmt.Sprintf("Only %.0f%% of images have alt text (aim for 80%%+)", altRatio*100)
 		}
 	} else {
 		message = "No images to optimize"
@@ -585,42 +725,11 @@ func (c *Checker) checkStructuredData() {
 	}
 }
 
-func (c *Checker) checkPageLoadingSpeed() {
-	// Simple heuristic based on content size and images
-	wordCount := c.analysis.Content.WordCount
-	imageCount := c.analysis.Images.TotalCount
-	
-	// Estimate load speed based on content complexity
-	loadScore := 100.0
-	if wordCount > 2000 {
-		loadScore -= 20 // Heavy text content
-	}
-	if imageCount > 10 {
-		loadScore -= 25 // Many images slow loading
-	}
-	if wordCount < 100 {
-		loadScore -= 15 // Very light content might lack substance
-	}
-	
-	passed := loadScore >= 70
-	message := fmt.Sprintf("Page loading characteristics score: %.0f/100", loadScore)
-	if !passed {
-		message = fmt.Sprintf("Page may load slowly (score: %.0f/100)", loadScore)
-	}
-
-	c.results["page_loading_speed"] = CheckResult{
-		Passed:  passed,
-		Value:   loadScore,
-		Message: message,
-		Weight:  c.weights["page_loading_speed"],
-	}
-}
-
 func (c *Checker) checkSocialMediaMeta() {
 	// Check for Open Graph or Twitter Card meta tags in the Meta map
 	hasOG := false
 	hasTwitter := false
-	
+
 	if c.analysis.Meta != nil {
 		for key := range c.analysis.Meta {
 			keyLower := strings.ToLower(key)
@@ -632,7 +741,7 @@ func (c *Checker) checkSocialMediaMeta() {
 			}
 		}
 	}
-	
+
 	passed := hasOG || hasTwitter
 	message := "Page has social media meta tags (Open Graph or Twitter Cards)"
 	if !passed {
@@ -647,12 +756,204 @@ func (c *Checker) checkSocialMediaMeta() {
 	}
 }
 
+func (c *Checker) checkResponseCompression() {
+	encoding := strings.ToLower(strings.TrimSpace(c.header("Content-Encoding")))
+	passed := false
+	for _, supported := range []string{"gzip", "br", "zstd", "deflate"} {
+		if strings.Contains(encoding, supported) {
+			passed = true
+		}
+	}
+
+	message := fmt.Sprintf("HTML response is compressed (%s)", encoding)
+	if !passed {
+		message = "HTML response is not compressed (enable gzip or brotli on the server)"
+	}
+
+	c.results["response_compression"] = CheckResult{
+		Passed:  passed,
+		Value:   encoding,
+		Message: message,
+		Weight:  c.weights["response_compression"],
+	}
+}
+
+func (c *Checker) checkResponseCaching() {
+	cacheControl := c.header("Cache-Control")
+	hasValidator := c.header("ETag") != "" || c.header("Last-Modified") != ""
+	passed := cacheControl != "" || c.header("Expires") != "" || hasValidator
+
+	message := "Response declares caching policy"
+	if cacheControl != "" {
+		message = fmt.Sprintf("Response declares caching policy (Cache-Control: %s)", cacheControl)
+	}
+	if !passed {
+		message = "Response has no Cache-Control, Expires, ETag or Last-Modified header"
+	}
+
+	c.results["response_caching"] = CheckResult{
+		Passed:  passed,
+		Value:   cacheControl,
+		Message: message,
+		Weight:  c.weights["response_caching"],
+	}
+}
+
+func (c *Checker) checkHSTSHeader() {
+	// HSTS only applies to pages served over HTTPS
+	if c.analysis.ParsedURL == nil || c.analysis.ParsedURL.Scheme != "https" {
+		return
+	}
+
+	hsts := c.header("Strict-Transport-Security")
+	passed := hsts != ""
+	message := "Page sends a Strict-Transport-Security header"
+	if !passed {
+		message = "HTTPS page is missing a Strict-Transport-Security (HSTS) header"
+	}
+
+	c.results["hsts_header"] = CheckResult{
+		Passed:  passed,
+		Value:   hsts,
+		Message: message,
+		Weight:  c.weights["hsts_header"],
+	}
+}
+
+// maxListedProblems caps how many errors or requests a check message lists
+const maxListedProblems = 3
+
+func (c *Checker) checkJavaScriptErrors() {
+	errors := c.page.Diagnostics.PageErrors
+	passed := len(errors) == 0
+	message := "Page runs without uncaught JavaScript exceptions"
+	if !passed {
+		message = fmt.Sprintf("Page throws %d uncaught JavaScript exceptions, which can break client-side rendering: %s",
+			len(errors), joinLimited(errors, maxListedProblems))
+	}
+
+	c.results["javascript_errors"] = CheckResult{
+		Passed:  passed,
+		Value:   len(errors),
+		Message: message,
+		Weight:  c.weights["javascript_errors"],
+	}
+}
+
+func (c *Checker) checkConsoleErrors() {
+	entries := c.page.Diagnostics.ConsoleErrors
+	passed := len(entries) == 0
+	message := "Page logs no console errors"
+	if !passed {
+		descriptions := make([]string, len(entries))
+		for i, entry := range entries {
+			descriptions[i] = entry.Message
+			if entry.Location != "" {
+				descriptions[i] += " (at " + entry.Location + ")"
+			}
+		}
+		message = fmt.Sprintf("Page logs %d console errors: %s", len(entries), joinLimited(descriptions, maxListedProblems))
+	}
+
+	c.results["console_errors"] = CheckResult{
+		Passed:  passed,
+		Value:   len(entries),
+		Message: message,
+		Weight:  c.weights["console_errors"],
+	}
+}
+
+func (c *Checker) checkFailedRequests() {
+	requests := c.page.Diagnostics.FailedRequests
+	passed := len(requests) == 0
+	message := "All scripts, stylesheets, images and fonts loaded"
+	if !passed {
+		descriptions := make([]string, len(requests))
+		for i, request := range requests {
+			reason := request.Error
+			if request.StatusCode != 0 {
+				reason = fmt.Sprintf("HTTP %d", request.StatusCode)
+			}
+			descriptions[i] = fmt.Sprintf("%s (%s, %s)", request.URL, request.ResourceType, reason)
+		}
+		message = fmt.Sprintf("%d resources failed to load: %s", len(requests), joinLimited(descriptions, maxListedProblems))
+	}
+
+	c.results["failed_requests"] = CheckResult{
+		Passed:  passed,
+		Value:   len(requests),
+		Message: message,
+		Weight:  c.weights["failed_requests"],
+	}
+}
+
+// joinLimited joins the first limit items, noting how many were left out
+func joinLimited(items []string, limit int) string {
+	if len(items) <= limit {
+		return strings.Join(items, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(items[:limit], "; "), len(items)-limit)
+}
+
+func (c *Checker) checkRedirectChainLength() {
+	hops := len(c.page.Redirects)
+	passed := hops <= 1
+	message := "Page is not reached through a redirect chain"
+	if hops == 1 {
+		message = "Page is reached through a single redirect"
+	} else if !passed {
+		message = fmt.Sprintf("Page is reached through a chain of %d redirects (%s)", hops, formatRedirectChain(c.page.Redirects, c.page.FinalURL))
+	}
+
+	c.results["redirect_chain_length"] = CheckResult{
+		Passed:  passed,
+		Value:   hops,
+		Message: message,
+		Weight:  c.weights["redirect_chain_length"],
+	}
+}
+
+func (c *Checker) checkRedirectLoop() {
+	passed := !c.page.RedirectLoop
+	message := "Page does not redirect in a loop"
+	if !passed {
+		// The loop closes at its first URL; pages stored without a chain only have their own
+		loopURL := c.page.URL
+		if len(c.page.Redirects) > 0 {
+			loopURL = c.page.Redirects[0].URL
+		}
+		message = fmt.Sprintf("Page redirects in a loop (%s)", formatRedirectChain(c.page.Redirects, loopURL))
+	}
+
+	c.results["redirect_loop"] = CheckResult{
+		Passed:  passed,
+		Value:   c.page.RedirectLoop,
+		Message: message,
+		Weight:  c.weights["redirect_loop"],
+	}
+}
+
+// formatRedirectChain renders a redirect chain as "url (301) -> url"
+func formatRedirectChain(hops []crawler.RedirectHop, finalURL string) string {
+	var parts []string
+	for _, hop := range hops {
+		parts = append(parts, fmt.Sprintf("%s (%d)", hop.URL, hop.StatusCode))
+	}
+	parts = append(parts, finalURL)
+	return strings.Join(parts, " -> ")
+}
+
 // calculateScore calculates the overall SEO score (0-100)
 func (c *Checker) calculateScore() float64 {
+	return calculateScore(c.results)
+}
+
+// calculateScore computes a weighted score (0-100) from check results
+func calculateScore(results map[string]CheckResult) float64 {
 	totalWeightedScore := 0.0
 	totalPossibleScore := 0.0
 
-	for _, result := range c.results {
+	for _, result := range results {
 		weight := float64(result.Weight)
 		if result.Passed {
 			totalWeightedScore += weight
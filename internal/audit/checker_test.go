@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ugolbck/seofordev/internal/crawler"
+)
+
+func TestRedirectChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		page        crawler.PageResult
+		check       string
+		wantPassed  bool
+		wantMessage string // Substring of the message
+	}{
+		{
+			name:        "no redirect",
+			page:        crawler.PageResult{URL: "http://localhost:3000/a"},
+			check:       "redirect_chain_length",
+			wantPassed:  true,
+			wantMessage: "not reached through a redirect chain",
+		},
+		{
+			name: "single redirect",
+			page: crawler.PageResult{
+				URL:       "http://localhost:3000/a",
+				FinalURL:  "http://localhost:3000/b",
+				Redirects: []crawler.RedirectHop{{URL: "http://localhost:3000/a", StatusCode: 301}},
+			},
+			check:       "redirect_chain_length",
+			wantPassed:  true,
+			wantMessage: "single redirect",
+		},
+		{
+			name: "chain",
+			page: crawler.PageResult{
+				URL:      "http://localhost:3000/a",
+				FinalURL: "http://localhost:3000/c",
+				Redirects: []crawler.RedirectHop{
+					{URL: "http://localhost:3000/a", StatusCode: 301},
+					{URL: "http://localhost:3000/b", StatusCode: 302},
+				},
+			},
+			check:       "redirect_chain_length",
+			wantPassed:  false,
+			wantMessage: "http://localhost:3000/a (301) -> http://localhost:3000/b (302) -> http://localhost:3000/c",
+		},
+		{
+			name:        "no loop",
+			page:        crawler.PageResult{URL: "http://localhost:3000/a"},
+			check:       "redirect_loop",
+			wantPassed:  true,
+			wantMessage: "does not redirect in a loop",
+		},
+		{
+			name: "loop",
+			page: crawler.PageResult{
+				URL:          "http://localhost:3000/a",
+				RedirectLoop: true,
+				Redirects: []crawler.RedirectHop{
+					{URL: "http://localhost:3000/a", StatusCode: 302},
+					{URL: "http://localhost:3000/b", StatusCode: 302},
+				},
+			},
+			check:       "redirect_loop",
+			wantPassed:  false,
+			wantMessage: "http://localhost:3000/a (302) -> http://localhost:3000/b (302) -> http://localhost:3000/a",
+		},
+		{
+			name:        "loop without a stored chain",
+			page:        crawler.PageResult{URL: "http://localhost:3000/a", RedirectLoop: true},
+			check:       "redirect_loop",
+			wantPassed:  false,
+			wantMessage: "in a loop (http://localhost:3000/a)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(nil, &tt.page)
+			checker.checkRedirectChainLength()
+			checker.checkRedirectLoop()
+
+			result := checker.results[tt.check]
+			if result.Passed != tt.wantPassed {
+				t.Errorf("%s passed = %v, want %v (%s)", tt.check, result.Passed, tt.wantPassed, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("%s message = %q, want it to contain %q", tt.check, result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestRunFailedPageChecks(t *testing.T) {
+	loop := crawler.PageResult{URL: "http://localhost:3000/a", RedirectLoop: true}
+	results := NewChecker(nil, &loop).RunFailedPageChecks()
+	if check, ok := results["redirect_loop"]; !ok || check.Passed {
+		t.Errorf("redirect_loop = %+v, want a failed check", check)
+	}
+
+	notFound := crawler.PageResult{URL: "http://localhost:3000/a", StatusCode: 404}
+	if results := NewChecker(nil, &notFound).RunFailedPageChecks(); len(results) != 0 {
+		t.Errorf("checks for a 404 page = %v, want none", results)
+	}
+}
+
+func TestDiagnosticsChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagnostics crawler.PageDiagnostics
+		check       string
+		wantPassed  bool
+		wantMessage string
+	}{
+		{
+			name:        "no exceptions",
+			check:       "javascript_errors",
+			wantPassed:  true,
+			wantMessage: "Page runs without uncaught JavaScript exceptions",
+		},
+		{
+			name:        "exceptions",
+			diagnostics: crawler.PageDiagnostics{PageErrors: []string{"a", "b", "c", "d", "e"}},
+			check:       "javascript_errors",
+			wantPassed:  false,
+			wantMessage: "Page throws 5 uncaught JavaScript exceptions, which can break client-side rendering: a; b; c; and 2 more",
+		},
+		{
+			name: "console errors",
+			diagnostics: crawler.PageDiagnostics{ConsoleErrors: []crawler.ConsoleError{
+				{Message: "boom", Location: "http://localhost:3000/app.js:3:7"},
+				{Message: "quiet"},
+			}},
+			check:       "console_errors",
+			wantPassed:  false,
+			wantMessage: "Page logs 2 console errors: boom (at http://localhost:3000/app.js:3:7); quiet",
+		},
+		{
+			name: "failed requests",
+			diagnostics: crawler.PageDiagnostics{FailedRequests: []crawler.FailedRequest{
+				{URL: "http://localhost:3000/app.css", ResourceType: "stylesheet", StatusCode: 404},
+				{URL: "https://cdn.example.com/lib.js", ResourceType: "script", Error: "net::ERR_NAME_NOT_RESOLVED"},
+			}},
+			check:       "failed_requests",
+			wantPassed:  false,
+			wantMessage: "2 resources failed to load: http://localhost:3000/app.css (stylesheet, HTTP 404); https://cdn.example.com/lib.js (script, net::ERR_NAME_NOT_RESOLVED)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := crawler.PageResult{URL: "http://localhost:3000/", Diagnostics: &tt.diagnostics}
+			checker := NewChecker(nil, &page)
+			checker.checkJavaScriptErrors()
+			checker.checkConsoleErrors()
+			checker.checkFailedRequests()
+
+			result := checker.results[tt.check]
+			if result.Passed != tt.wantPassed {
+				t.Errorf("%s passed = %v, want %v", tt.check, result.Passed, tt.wantPassed)
+			}
+			if result.Message != tt.wantMessage {
+				t.Errorf("%s message = %q, want %q", tt.check, result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestJoinLimited(t *testing.T) {
+	tests := []struct {
+		items []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"a"}, "a"},
+		{[]string{"a", "b", "c"}, "a; b; c"},
+		{[]string{"a", "b", "c", "d"}, "a; b; c; and 1 more"},
+	}
+
+	for _, tt := range tests {
+		if got := joinLimited(tt.items, 3); got != tt.want {
+			t.Errorf("joinLimited(%q, 3) = %q, want %q", tt.items, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ugolbck/seofordev/internal/crawler"
+)
+
+// minContentParity is the share of the other render's words the primary render must keep
+const minContentParity = 0.8
+
+// deviceAnalysis is the analysis of a page rendered on another device
+type deviceAnalysis struct {
+	device   string
+	analysis *AnalysisResult
+}
+
+// AddDeviceRender adds the analysis of the page rendered on another device,
+// which RunAllChecks compares with the primary render
+func (c *Checker) AddDeviceRender(device string, analysis *AnalysisResult) {
+	c.renders = append(c.renders, deviceAnalysis{device: device, analysis: analysis})
+}
+
+// runParityChecks compares the primary render with the renders on other devices
+func (c *Checker) runParityChecks() {
+	if c.page.Device == crawler.DeviceMobile && c.page.Viewport != nil {
+		c.checkHorizontalOverflow()
+	}
+
+	for _, render := range c.renders {
+		c.checkContentParity(render)
+		c.checkLinkParity(render)
+		c.checkTitleParity(render)
+		c.checkStructuredDataParity(render)
+	}
+}
+
+// primaryDevice names the device of the primary render in messages
+func (c *Checker) primaryDevice() string {
+	if c.page.Device == "" {
+		return "primary"
+	}
+	return c.page.Device
+}
+
+func (c *Checker) checkHorizontalOverflow() {
+	overflow := c.page.Viewport.Overflow()
+	passed := overflow == 0
+	message := "Page fits the mobile viewport without horizontal scrolling"
+	if !passed {
+		message = fmt.Sprintf("Page is %dpx wider than the %dpx mobile viewport and scrolls horizontally",
+			overflow, c.page.Viewport.Width)
+	}
+
+	c.results["horizontal_overflow"] = CheckResult{
+		Passed:  passed,
+		Value:   overflow,
+		Message: message,
+		Weight:  c.weights["horizontal_overflow"],
+	}
+}
+
+func (c *Checker) checkContentParity(render deviceAnalysis) {
+	words := c.analysis.Content.WordCount
+	otherWords := render.analysis.Content.WordCount
+
+	ratio := 1.0
+	if otherWords > 0 {
+		ratio = float64(words) / float64(otherWords)
+	}
+
+	passed := ratio >= minContentParity
+	message := fmt.Sprintf("The %s render has as much content as the %s render (%d vs %d words)",
+		c.primaryDevice(), render.device, words, otherWords)
+	if !passed {
+		message = fmt.Sprintf("The %s render is missing content shown on %s (%d vs %d words)",
+			c.primaryDevice(), render.device, words, otherWords)
+	}
+
+	c.results["content_parity"] = CheckResult{
+		Passed:  passed,
+		Value:   fmt.Sprintf("%.0f%%", ratio*100),
+		Message: message,
+		Weight:  c.weights["content_parity"],
+	}
+}
+
+func (c *Checker) checkLinkParity(render deviceAnalysis) {
+	present := make(map[string]bool, len(c.analysis.Links.Internal))
+	for _, link := range c.analysis.Links.Internal {
+		present[link.URL] = true
+	}
+
+	missingSet := make(map[string]bool)
+	for _, link := range render.analysis.Links.Internal {
+		if !present[link.URL] {
+			missingSet[link.URL] = true
+		}
+	}
+	missing := make([]string, 0, len(missingSet))
+	for u := range missingSet {
+		missing = append(missing, u)
+	}
+	sort.Strings(missing)
+
+	passed := len(missing) == 0
+	message := fmt.Sprintf("The %s render has every internal link found on %s", c.primaryDevice(), render.device)
+	if !passed {
+		shown := missing
+		if len(shown) > 5 {
+			shown = shown[:5]
+		}
+		message = fmt.Sprintf("The %s render is missing %d internal links found on %s: %s",
+			c.primaryDevice(), len(missing), render.device, strings.Join(shown, ", "))
+	}
+
+	c.results["link_parity"] = CheckResult{
+		Passed:  passed,
+		Value:   len(missing),
+		Message: message,
+		Weight:  c.weights["link_parity"],
+	}
+}
+
+func (c *Checker) checkTitleParity(render deviceAnalysis) {
+	title := strings.TrimSpace(c.analysis.Title)
+	otherTitle := strings.TrimSpace(render.analysis.Title)
+
+	passed := title == otherTitle
+	message := fmt.Sprintf("The %s and %s renders have the same title", c.primaryDevice(), render.device)
+	if !passed {
+		message = fmt.Sprintf("The title differs between %s (%q) and %s (%q)",
+			c.primaryDevice(), title, render.device, otherTitle)
+	}
+
+	c.results["title_parity"] = CheckResult{
+		Passed:  passed,
+		Value:   passed,
+		Message: message,
+		Weight:  c.weights["title_parity"],
+	}
+}
+
+func (c *Checker) checkStructuredDataParity(render deviceAnalysis) {
+	schema := c.analysis.Schema
+	otherSchema := render.analysis.Schema
+
+	passed := schema.HasStructuredData == otherSchema.HasStructuredData &&
+		strings.Join(schema.Types, ",") == strings.Join(otherSchema.Types, ",")
+	message := fmt.Sprintf("The %s and %s renders have the same structured data", c.primaryDevice(), render.device)
+	if !passed {
+		message = fmt.Sprintf("Structured data differs between %s (%s) and %s (%s)",
+			c.primaryDevice(), describeSchema(schema), render.device, describeSchema(otherSchema))
+	}
+
+	c.results["structured_data_parity"] = CheckResult{
+		Passed:  passed,
+		Value:   passed,
+		Message: message,
+		Weight:  c.weights["structured_data_parity"],
+	}
+}
+
+// describeSchema summarizes structured data for parity messages
+func describeSchema(schema *SchemaData) string {
+	switch {
+	case len(schema.Types) > 0:
+		return strings.Join(schema.Types, ", ")
+	case schema.HasStructuredData:
+		return "untyped"
+	default:
+		return "none"
+	}
+}
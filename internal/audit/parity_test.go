@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ugolbck/seofordev/internal/crawler"
+)
+
+// renderPage builds an HTML page for parity tests
+func renderPage(title, body string) string {
+	return "<!DOCTYPE html><html><head><title>" + title + "</title></head><body>" + body + "</body></html>"
+}
+
+// analyze runs the analyzer on html as served from http://localhost:3000/
+func analyze(t *testing.T, html string) *AnalysisResult {
+	t.Helper()
+	result, err := NewAnalyzer().AnalyzeContent(html, "http://localhost:3000/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestParityChecks(t *testing.T) {
+	const article = "<p>one two three four five six seven eight nine ten</p>"
+	const productSchema = `<script type="application/ld+json">{"@type": "Product", "name": "Shoe"}</script>`
+
+	tests := []struct {
+		name        string
+		mobile      string
+		desktop     string
+		check       string
+		wantPassed  bool
+		wantMessage string // Substring of the message
+	}{
+		{
+			name:        "same content",
+			mobile:      renderPage("Home", article),
+			desktop:     renderPage("Home", article),
+			check:       "content_parity",
+			wantPassed:  true,
+			wantMessage: "The mobile render has as much content as the desktop render",
+		},
+		{
+			name:        "mobile hides content",
+			mobile:      renderPage("Home", "<p>one two three</p>"),
+			desktop:     renderPage("Home", article),
+			check:       "content_parity",
+			wantPassed:  false,
+			wantMessage: "missing content shown on desktop",
+		},
+		{
+			name:        "mobile has more content",
+			mobile:      renderPage("Home", article+article),
+			desktop:     renderPage("Home", article),
+			check:       "content_parity",
+			wantPassed:  true,
+			wantMessage: "as much content",
+		},
+		{
+			name:        "same links in another order",
+			mobile:      renderPage("Home", `<a href="/b">B</a><a href="/a">A</a>`),
+			desktop:     renderPage("Home", `<a href="/a">A</a><a href="/b">B</a><a href="https://example.com/">External</a>`),
+			check:       "link_parity",
+			wantPassed:  true,
+			wantMessage: "has every internal link found on desktop",
+		},
+		{
+			name:        "mobile drops links",
+			mobile:      renderPage("Home", `<a href="/a">A</a>`),
+			desktop:     renderPage("Home", `<a href="/a">A</a><a href="/pricing">Pricing</a><a href="/blog">Blog</a><a href="/blog">Blog</a>`),
+			check:       "link_parity",
+			wantPassed:  false,
+			wantMessage: "missing 2 internal links found on desktop: http://localhost:3000/blog, http://localhost:3000/pricing",
+		},
+		{
+			name:        "same title",
+			mobile:      renderPage(" Home ", article),
+			desktop:     renderPage("Home", article),
+			check:       "title_parity",
+			wantPassed:  true,
+			wantMessage: "same title",
+		},
+		{
+			name:        "different title",
+			mobile:      renderPage("Home", article),
+			desktop:     renderPage("Home | Shop", article),
+			check:       "title_parity",
+			wantPassed:  false,
+			wantMessage: `The title differs between mobile ("Home") and desktop ("Home | Shop")`,
+		},
+		{
+			name:        "same structured data",
+			mobile:      renderPage("Home", productSchema+article),
+			desktop:     renderPage("Home", productSchema+article),
+			check:       "structured_data_parity",
+			wantPassed:  true,
+			wantMessage: "same structured data",
+		},
+		{
+			name:        "structured data only on desktop",
+			mobile:      renderPage("Home", article),
+			desktop:     renderPage("Home", productSchema+article),
+			check:       "structured_data_parity",
+			wantPassed:  false,
+			wantMessage: "Structured data differs between mobile (none) and desktop (Product)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &crawler.PageResult{URL: "http://localhost:3000/", StatusCode: 200, Device: crawler.DeviceMobile}
+			checker := NewChecker(analyze(t, tt.mobile), page)
+			checker.AddDeviceRender(crawler.DeviceDesktop, analyze(t, tt.desktop))
+			checker.runParityChecks()
+
+			result, ok := checker.results[tt.check]
+			if !ok {
+				t.Fatalf("%s was not checked", tt.check)
+			}
+			if result.Passed != tt.wantPassed {
+				t.Errorf("%s passed = %v, want %v (%s)", tt.check, result.Passed, tt.wantPassed, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("%s message = %q, want it to contain %q", tt.check, result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParityChecksWithoutRenders(t *testing.T) {
+	page := &crawler.PageResult{URL: "http://localhost:3000/", StatusCode: 200}
+	checker := NewChecker(analyze(t, renderPage("Home", "<p>Hello</p>")), page)
+	checker.runParityChecks()
+	if len(checker.results) != 0 {
+		t.Errorf("parity results without other renders = %v, want none", checker.results)
+	}
+}
+
+func TestHorizontalOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		device   string
+		viewport *crawler.ViewportMetrics
+		want     *CheckResult // Nil when the check does not run
+	}{
+		{
+			name:     "fits",
+			device:   crawler.DeviceMobile,
+			viewport: &crawler.ViewportMetrics{Width: 412, ScrollWidth: 412},
+			want:     &CheckResult{Passed: true, Value: 0},
+		},
+		{
+			name:     "overflows",
+			device:   crawler.DeviceMobile,
+			viewport: &crawler.ViewportMetrics{Width: 412, ScrollWidth: 600},
+			want:     &CheckResult{Passed: false, Value: 188, Message: "Page is 188px wider than the 412px mobile viewport and scrolls horizontally"},
+		},
+		{name: "not measured", device: crawler.DeviceMobile},
+		{name: "desktop", device: crawler.DeviceDesktop, viewport: &crawler.ViewportMetrics{Width: 1366, ScrollWidth: 2000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &crawler.PageResult{URL: "http://localhost:3000/", StatusCode: 200, Device: tt.device, Viewport: tt.viewport}
+			checker := NewChecker(analyze(t, renderPage("Home", "")), page)
+			checker.runParityChecks()
+
+			result, ok := checker.results["horizontal_overflow"]
+			if tt.want == nil {
+				if ok {
+					t.Errorf("horizontal_overflow = %+v, want it skipped", result)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("horizontal_overflow was not checked")
+			}
+			if result.Passed != tt.want.Passed || result.Value != tt.want.Value {
+				t.Errorf("horizontal_overflow = %v (%v), want %v (%v)", result.Passed, result.Value, tt.want.Passed, tt.want.Value)
+			}
+			if tt.want.Message != "" && result.Message != tt.want.Message {
+				t.Errorf("message = %q, want %q", result.Message, tt.want.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package audit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ugolbck/seofordev/internal/crawler"
+)
+
+// PerformanceBudget sets the limits the page_loading_speed check enforces.
+// Nil fields fall back to DefaultBudget; zero is a valid limit (e.g. cls=0).
+type PerformanceBudget struct {
+	TTFBMs     *int     `json:"ttfb_ms,omitempty"`
+	LCPMs      *int     `json:"lcp_ms,omitempty"`
+	CLS        *float64 `json:"cls,omitempty"`
+	TransferKB *int     `json:"transfer_kb,omitempty"`
+	Requests   *int     `json:"requests,omitempty"`
+}
+
+// DefaultBudget follows the Core Web Vitals "good" thresholds
+func DefaultBudget() PerformanceBudget {
+	return PerformanceBudget{
+		TTFBMs:     intLimit(800),
+		LCPMs:      intLimit(2500),
+		CLS:        floatLimit(0.1),
+		TransferKB: intLimit(3072),
+		Requests:   intLimit(100),
+	}
+}
+
+// WithDefaults fills unset limits from DefaultBudget, so every field is set
+func (b PerformanceBudget) WithDefaults() PerformanceBudget {
+	defaults := DefaultBudget()
+	if b.TTFBMs == nil {
+		b.TTFBMs = defaults.TTFBMs
+	}
+	if b.LCPMs == nil {
+		b.LCPMs = defaults.LCPMs
+	}
+	if b.CLS == nil {
+		b.CLS = defaults.CLS
+	}
+	if b.TransferKB == nil {
+		b.TransferKB = defaults.TransferKB
+	}
+	if b.Requests == nil {
+		b.Requests = defaults.Requests
+	}
+	return b
+}
+
+func intLimit(value int) *int {
+	return &value
+}
+
+func floatLimit(value float64) *float64 {
+	return &value
+}
+
+// PerformanceMetrics represents the loading metrics measured in the browser
+type PerformanceMetrics struct {
+	TTFBMs             int     `json:"ttfb_ms"`
+	DOMContentLoadedMs int     `json:"dom_content_loaded_ms"`
+	LoadMs             int     `json:"load_ms"`
+	LCPMs              *int    `json:"lcp_ms,omitempty"` // nil when the browser reported no LCP
+	CLS                float64 `json:"cls"`
+	TransferBytes      int64   `json:"transfer_bytes"`
+	Requests           int     `json:"requests"`
+}
+
+// convertPerformance converts crawler metrics to their stored form
+func convertPerformance(metrics *crawler.PerformanceMetrics) *PerformanceMetrics {
+	if metrics == nil {
+		return nil
+	}
+	converted := &PerformanceMetrics{
+		TTFBMs:             int(metrics.TTFB.Milliseconds()),
+		DOMContentLoadedMs: int(metrics.DOMContentLoaded.Milliseconds()),
+		LoadMs:             int(metrics.Load.Milliseconds()),
+		CLS:                metrics.CLS,
+		TransferBytes:      metrics.TransferBytes,
+		Requests:           metrics.Requests,
+	}
+	if metrics.HasLCP {
+		converted.LCPMs = intLimit(int(metrics.LCP.Milliseconds()))
+	}
+	return converted
+}
+
+// SetBudget sets the performance budget checked by page_loading_speed
+func (c *Checker) SetBudget(budget PerformanceBudget) {
+	c.budget = budget.WithDefaults()
+}
+
+// checkPageLoadingSpeed compares the browser's loading metrics with the budget
+func (c *Checker) checkPageLoadingSpeed() {
+	metrics := convertPerformance(c.page.Performance)
+	budget := c.budget // Every limit is set, see SetBudget
+	transferBytes := int64(*budget.TransferKB) * 1024
+
+	var exceeded []string
+	if metrics.TTFBMs > *budget.TTFBMs {
+		exceeded = append(exceeded, fmt.Sprintf("TTFB %s > %s", formatMillis(metrics.TTFBMs), formatMillis(*budget.TTFBMs)))
+	}
+	// A page without an LCP entry is not measured against the LCP budget
+	lcp := "not reported"
+	if metrics.LCPMs != nil {
+		lcp = formatMillis(*metrics.LCPMs)
+		if *metrics.LCPMs > *budget.LCPMs {
+			exceeded = append(exceeded, fmt.Sprintf("LCP %s > %s", lcp, formatMillis(*budget.LCPMs)))
+		}
+	}
+	if metrics.CLS > *budget.CLS {
+		exceeded = append(exceeded, fmt.Sprintf("CLS %.2f > %.2f", metrics.CLS, *budget.CLS))
+	}
+	if metrics.TransferBytes > transferBytes {
+		exceeded = append(exceeded, fmt.Sprintf("transfer %s > %s", formatBytes(metrics.TransferBytes), formatBytes(transferBytes)))
+	}
+	if metrics.Requests > *budget.Requests {
+		exceeded = append(exceeded, fmt.Sprintf("%d requests > %d", metrics.Requests, *budget.Requests))
+	}
+
+	summary := fmt.Sprintf("TTFB %s, LCP %s, CLS %.2f, %s in %d requests",
+		formatMillis(metrics.TTFBMs), lcp, metrics.CLS, formatBytes(metrics.TransferBytes), metrics.Requests)
+
+	passed := len(exceeded) == 0
+	message := fmt.Sprintf("Page is within its performance budget (%s)", summary)
+	if !passed {
+		message = fmt.Sprintf("Page exceeds its performance budget: %s", strings.Join(exceeded, "; "))
+	}
+	if metrics.LCPMs == nil {
+		message += ". LCP was not reported, so it is not checked"
+	}
+
+	c.results["page_loading_speed"] = CheckResult{
+		Passed:  passed,
+		Value:   summary,
+		Message: message,
+		Weight:  c.weights["page_loading_speed"],
+	}
+}
+
+func formatMillis(ms int) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.1fs", float64(ms)/1000)
+	}
+	return fmt.Sprintf("%dms", ms)
+}
+
+func formatBytes(bytes int64) string {
+	switch {
+	case bytes >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+	case bytes >= 1024:
+		return fmt.Sprintf("%.0f KB", float64(bytes)/1024)
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
+
+// Set applies one key=value limit, e.g. ttfb=600ms, lcp=2.5s, cls=0.1,
+// transfer=2MB or requests=80
+func (b *PerformanceBudget) Set(entry string) error {
+	key, value, ok := strings.Cut(entry, "=")
+	if !ok {
+		return fmt.Errorf("invalid budget %q (expected key=value)", entry)
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+
+	switch key {
+	case "ttfb", "lcp":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			// Bare numbers are milliseconds
+			ms, numErr := strconv.Atoi(value)
+			if numErr != nil {
+				return fmt.Errorf("invalid %s budget %q: %w", key, value, err)
+			}
+			d = time.Duration(ms) * time.Millisecond
+		}
+		if d < 0 {
+			return fmt.Errorf("invalid %s budget %q: must not be negative", key, value)
+		}
+		if key == "ttfb" {
+			b.TTFBMs = intLimit(int(d.Milliseconds()))
+		} else {
+			b.LCPMs = intLimit(int(d.Milliseconds()))
+		}
+	case "cls":
+		cls, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid cls budget %q: %w", value, err)
+		}
+		if cls < 0 {
+			return fmt.Errorf("invalid cls budget %q: must not be negative", value)
+		}
+		b.CLS = floatLimit(cls)
+	case "transfer":
+		kb, err := parseKilobytes(value)
+		if err != nil {
+			return fmt.Errorf("invalid transfer budget %q: %w", value, err)
+		}
+		if kb < 0 {
+			return fmt.Errorf("invalid transfer budget %q: must not be negative", value)
+		}
+		b.TransferKB = intLimit(kb)
+	case "requests":
+		requests, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid requests budget %q: %w", value, err)
+		}
+		if requests < 0 {
+			return fmt.Errorf("invalid requests budget %q: must not be negative", value)
+		}
+		b.Requests = intLimit(requests)
+	default:
+		return fmt.Errorf("unknown budget %q (expected ttfb, lcp, cls, transfer or requests)", key)
+	}
+	return nil
+}
+
+// parseKilobytes reads a size such as 500KB or 2MB; bare numbers are kilobytes
+func parseKilobytes(value string) (int, error) {
+	upper := strings.ToUpper(value)
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(upper, "MB"):
+		multiplier, upper = 1024, strings.TrimSuffix(upper, "MB")
+	case strings.HasSuffix(upper, "KB"):
+		upper = strings.TrimSuffix(upper, "KB")
+	}
+	size, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil {
+		return 0, err
+	}
+	return int(size * multiplier), nil
+}
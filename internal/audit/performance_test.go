@@ -0,0 +1,157 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ugolbck/seofordev/internal/crawler"
+)
+
+func TestBudgetSet(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    string // Resolved budget as JSON
+		wantErr bool
+	}{
+		{entry: "ttfb=600ms", want: `{"ttfb_ms":600,"lcp_ms":2500,"cls":0.1,"transfer_kb":3072,"requests":100}`},
+		{entry: "lcp=2.5s", want: `{"ttfb_ms":800,"lcp_ms":2500,"cls":0.1,"transfer_kb":3072,"requests":100}`},
+		{entry: "lcp=1200", want: `{"ttfb_ms":800,"lcp_ms":1200,"cls":0.1,"transfer_kb":3072,"requests":100}`},
+		{entry: "transfer=2MB", want: `{"ttfb_ms":800,"lcp_ms":2500,"cls":0.1,"transfer_kb":2048,"requests":100}`},
+		{entry: "transfer=500", want: `{"ttfb_ms":800,"lcp_ms":2500,"cls":0.1,"transfer_kb":500,"requests":100}`},
+		{entry: " Requests = 80 ", want: `{"ttfb_ms":800,"lcp_ms":2500,"cls":0.1,"transfer_kb":3072,"requests":80}`},
+
+		// Zero is a limit, not a request for the default
+		{entry: "cls=0", want: `{"ttfb_ms":800,"lcp_ms":2500,"cls":0,"transfer_kb":3072,"requests":100}`},
+		{entry: "requests=0", want: `{"ttfb_ms":800,"lcp_ms":2500,"cls":0.1,"transfer_kb":3072,"requests":0}`},
+		{entry: "ttfb=0", want: `{"ttfb_ms":0,"lcp_ms":2500,"cls":0.1,"transfer_kb":3072,"requests":100}`},
+
+		{entry: "cls=-0.1", wantErr: true},
+		{entry: "ttfb=-1s", wantErr: true},
+		{entry: "requests=-1", wantErr: true},
+		{entry: "lcp=fast", wantErr: true},
+		{entry: "fcp=1s", wantErr: true},
+		{entry: "cls", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var budget PerformanceBudget
+		err := budget.Set(tt.entry)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Set(%q) error = %v, want error %v", tt.entry, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		got, _ := json.Marshal(budget.WithDefaults())
+		if string(got) != tt.want {
+			t.Errorf("Set(%q) = %s, want %s", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestBudgetStoredZero(t *testing.T) {
+	var budget PerformanceBudget
+	if err := budget.Set("cls=0"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A resumed audit restores the stored budget and resolves it again
+	data, err := json.Marshal(budget.WithDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored PerformanceBudget
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if cls := *stored.WithDefaults().CLS; cls != 0 {
+		t.Errorf("restored CLS budget = %v, want 0", cls)
+	}
+
+	// Budgets stored before limits were optional still load
+	var old PerformanceBudget
+	if err := json.Unmarshal([]byte(`{"ttfb_ms":600}`), &old); err != nil {
+		t.Fatal(err)
+	}
+	old = old.WithDefaults()
+	if *old.TTFBMs != 600 || *old.LCPMs != 2500 {
+		t.Errorf("old budget = ttfb %d, lcp %d, want 600 and the default 2500", *old.TTFBMs, *old.LCPMs)
+	}
+}
+
+func TestCheckPageLoadingSpeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		metrics     crawler.PerformanceMetrics
+		budget      string
+		wantPassed  bool
+		wantMessage string // Substring of the message
+	}{
+		{
+			name:        "within budget",
+			metrics:     crawler.PerformanceMetrics{TTFB: 200 * time.Millisecond, LCP: 1200 * time.Millisecond, HasLCP: true, Requests: 10},
+			wantPassed:  true,
+			wantMessage: "LCP 1.2s",
+		},
+		{
+			name:        "slow LCP",
+			metrics:     crawler.PerformanceMetrics{TTFB: 200 * time.Millisecond, LCP: 4 * time.Second, HasLCP: true, Requests: 10},
+			wantPassed:  false,
+			wantMessage: "LCP 4.0s > 2.5s",
+		},
+		{
+			name:        "missing LCP is not checked",
+			metrics:     crawler.PerformanceMetrics{TTFB: 200 * time.Millisecond, Requests: 10},
+			budget:      "lcp=0",
+			wantPassed:  true,
+			wantMessage: "LCP was not reported, so it is not checked",
+		},
+		{
+			name:        "missing LCP with another limit exceeded",
+			metrics:     crawler.PerformanceMetrics{TTFB: 2 * time.Second, Requests: 10},
+			wantPassed:  false,
+			wantMessage: "TTFB 2.0s > 800ms. LCP was not reported",
+		},
+		{
+			name:        "zero CLS budget",
+			metrics:     crawler.PerformanceMetrics{CLS: 0.01, LCP: time.Second, HasLCP: true, Requests: 1},
+			budget:      "cls=0",
+			wantPassed:  false,
+			wantMessage: "CLS 0.01 > 0.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var budget PerformanceBudget
+			if tt.budget != "" {
+				if err := budget.Set(tt.budget); err != nil {
+					t.Fatal(err)
+				}
+			}
+			checker := NewChecker(&AnalysisResult{}, &crawler.PageResult{StatusCode: 200, Performance: &tt.metrics})
+			checker.SetBudget(budget)
+			checker.checkPageLoadingSpeed()
+
+			result := checker.results["page_loading_speed"]
+			if result.Passed != tt.wantPassed {
+				t.Errorf("Passed = %v, want %v (%s)", result.Passed, tt.wantPassed, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+
+	// Metrics stored without an LCP load as unknown
+	var stored PerformanceMetrics
+	if err := json.Unmarshal([]byte(`{"ttfb_ms":120,"cls":0,"requests":3}`), &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.LCPMs != nil {
+		t.Errorf("LCPMs = %d, want nil", *stored.LCPMs)
+	}
+}
@@ -1,13 +1,16 @@
 package audit
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ugolbck/seofordev/internal/crawler"
+	"github.com/ugolbck/seofordev/internal/events"
 )
 
 // ProcessorStatus represents the status of audit processing
@@ -17,6 +20,7 @@ const (
 	StatusDiscovering ProcessorStatus = "discovering"
 	StatusAnalyzing   ProcessorStatus = "analyzing" 
 	StatusCompleted   ProcessorStatus = "completed"
+	StatusPartial     ProcessorStatus = "partial" // Interrupted; pages fetched before the interruption were analyzed
 	StatusFailed      ProcessorStatus = "failed"
 )
 
@@ -30,6 +34,19 @@ const (
 	PageStatusFailed    PageStatus = "failed"
 )
 
+// storedHeaders lists the response headers kept with each page analysis
+var storedHeaders = []string{
+	"content-type",
+	"content-encoding",
+	"cache-control",
+	"expires",
+	"etag",
+	"last-modified",
+	"x-robots-tag",
+	"link",
+	"strict-transport-security",
+}
+
 // Processor handles the complete audit workflow locally
 type Processor struct {
 	storage    *LocalStorage
@@ -37,6 +54,9 @@ type Processor struct {
 	audit      *LocalAudit
 	mu         sync.RWMutex
 	processing map[string]bool // Track which pages are being processed
+
+	OnEvent events.Handler    // Optional; receives page analysis and completion events
+	Budget  PerformanceBudget // Limits for page_loading_speed; nil fields use DefaultBudget
 }
 
 // NewProcessor creates a new audit processor
@@ -69,64 +89,132 @@ func (p *Processor) StartAudit(baseURL string, config AuditConfig) (*LocalAudit,
 	return audit, nil
 }
 
-// SubmitPages processes discovered pages and starts analysis
-func (p *Processor) SubmitPages(auditID string, pages []crawler.PageResult) error {
+// ResumeAudit reopens an interrupted audit so crawling can continue
+func (p *Processor) ResumeAudit(auditID string) (*LocalAudit, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	audit, err := p.storage.LoadAudit(auditID)
+	if err != nil {
+		return nil, fmt.Errorf("audit not found: %w", err)
+	}
+	if audit.Status == string(StatusCompleted) {
+		return nil, fmt.Errorf("audit %s is already completed", auditID)
+	}
+
+	audit.Status = string(StatusDiscovering)
+	if err := p.storage.UpdateStatus(audit.ID, audit.Status); err != nil {
+		return nil, err
+	}
+
+	p.audit = audit
+	log.Printf("🔁 Resuming audit: %s for %s (%d pages already analyzed)", audit.ID, audit.BaseURL, len(audit.Pages))
+
+	return audit, nil
+}
+
+// AuditDir returns the directory for an audit's checkpoints and other files
+func (p *Processor) AuditDir(auditID string) (string, error) {
+	return p.storage.AuditDir(auditID)
+}
+
+// SubmitPages analyzes pages as the crawler streams them in. It returns once the
+// channel is closed and every received page has been analyzed. Page HTML is only
+// held until its analysis is stored.
+//
+// Cancelling ctx does not drop pages: the crawler stops fetching and closes the
+// channel, and pages already fetched are still analyzed. SubmitPages then
+// returns ctx's error so the caller can finalize a partial audit.
+func (p *Processor) SubmitPages(ctx context.Context, auditID string, pages <-chan crawler.PageResult) error {
+	p.mu.Lock()
 	if p.audit == nil || p.audit.ID != auditID {
 		// Load audit if not in memory
 		audit, err := p.storage.LoadAudit(auditID)
 		if err != nil {
+			p.mu.Unlock()
 			return fmt.Errorf("audit not found: %w", err)
 		}
 		p.audit = audit
 	}
 
-	// Update audit status
+	// Update audit status; a resumed audit already holds pages from the earlier run
 	p.audit.Status = string(StatusAnalyzing)
-	p.audit.PagesDiscovered = len(pages)
+	p.audit.PagesDiscovered = len(p.audit.Pages)
+	if err := p.storage.UpdateStatus(auditID, p.audit.Status); err != nil {
+		p.mu.Unlock()
+		return err
+	}
 
-	// Start processing pages concurrently
+	// Process pages concurrently
 	concurrency := p.audit.Config.Concurrency
 	if concurrency <= 0 {
 		concurrency = 3 // Default concurrency
 	}
+	p.mu.Unlock()
+
+	log.Printf("📥 Processing pages with concurrency %d", concurrency)
 
-	// Create a channel to control concurrency
-	semaphore := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-	log.Printf("📥 Processing %d pages with concurrency %d", len(pages), concurrency)
+			for page := range pages {
+				p.mu.Lock()
+				p.audit.PagesDiscovered++
+				p.mu.Unlock()
 
-	for _, page := range pages {
-		// Skip failed pages (status code != 200)
-		if page.StatusCode != 200 {
-			p.addFailedPage(page)
-			continue
-		}
+				// Failed pages (status code != 200) are recorded without analysis
+				if page.StatusCode != 200 {
+					p.addFailedPage(page)
+					continue
+				}
 
-		wg.Add(1)
-		go func(pageData crawler.PageResult) {
-			defer wg.Done()
-			
-			// Acquire semaphore
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-			
-			if err := p.processPage(pageData); err != nil {
-				log.Printf("❌ Failed to process page %s: %v", pageData.URL, err)
+				if err := p.processPage(page); err != nil {
+					log.Printf("❌ Failed to process page %s: %v", page.URL, err)
+				}
 			}
-		}(page)
+		}()
 	}
 
-	// Wait for all pages to be processed
-	go func() {
-		wg.Wait()
-		p.completeAudit()
-	}()
+	wg.Wait()
+	return ctx.Err()
+}
 
-	return nil
+// SetSitemapCoverage records how the crawled pages compare with the site's sitemaps
+func (p *Processor) SetSitemapCoverage(auditID string, coverage crawler.SitemapCoverage) error {
+	return p.storage.UpdateSitemapCoverage(auditID, SitemapCoverage{
+		SitemapURLs:  coverage.SitemapURLs,
+		Unlinked:     coverage.Unlinked,
+		NotInSitemap: coverage.NotInSitemap,
+	})
+}
+
+// SetCrawlStats records how much of the discovered site was crawled
+func (p *Processor) SetCrawlStats(auditID string, stats crawler.CrawlStats) error {
+	return p.storage.UpdateCrawlStats(auditID, CrawlStats{
+		PagesCrawled:    stats.PagesCrawled,
+		NotCrawled:      stats.NotCrawled,
+		DurationSeconds: stats.Duration.Seconds(),
+		PagesPerSecond:  stats.PagesPerSecond,
+		CrawlDelay:      stats.CrawlDelay.Seconds(),
+		Throttled:       stats.Throttled,
+		Skipped:         convertSkipped(stats.Skipped),
+	})
+}
+
+// convertSkipped converts the crawler's skipped URLs to their stored form
+func convertSkipped(skipped []crawler.SkippedURL) []SkippedURL {
+	if len(skipped) == 0 {
+		return nil
+	}
+
+	converted := make([]SkippedURL, len(skipped))
+	for i, skip := range skipped {
+		converted[i] = SkippedURL{URL: skip.URL, Reason: skip.Reason}
+	}
+	return converted
 }
 
 // processPage analyzes a single page
@@ -151,20 +239,58 @@ func (p *Processor) processPage(pageData crawler.PageResult) error {
 		URL:            pageData.URL,
 		StatusCode:     pageData.StatusCode,
 		Depth:          pageData.Depth,
+		Source:         pageData.Source,
+		LinkKind:       pageData.LinkKind,
+		Device:         pageData.Device,
 		AnalysisStatus: string(PageStatusAnalyzing),
 	}
 
+	// Record redirects followed to reach the page
+	page.FinalURL = pageData.FinalURL
+	page.RedirectChain = convertRedirects(pageData.Redirects)
+	page.ResponseHeaders = filterHeaders(pageData.Headers)
+
+	// Record runtime errors and broken assets
+	if diag := pageData.Diagnostics; diag != nil {
+		page.ConsoleErrors = convertConsoleErrors(diag.ConsoleErrors)
+		page.PageErrors = diag.PageErrors
+		page.FailedRequests = convertFailedRequests(diag.FailedRequests)
+	}
+	page.Performance = convertPerformance(pageData.Performance)
+	page.Screenshots = p.convertScreenshots(pageData.Screenshots)
+
+	// Analyze content against the URL it was actually served from
+	contentURL := pageData.URL
+	if pageData.FinalURL != "" {
+		contentURL = pageData.FinalURL
+	}
+
 	// Perform SEO analysis
-	analysis, err := p.analyzer.AnalyzeContent(pageData.Content, pageData.URL)
+	analysis, err := p.analyzer.AnalyzeContent(pageData.Content, contentURL)
 	if err != nil {
 		log.Printf("❌ Analysis failed for %s: %v", pageData.URL, err)
 		page.AnalysisStatus = string(PageStatusFailed)
 		page.IndexabilityReason = fmt.Sprintf("Analysis failed: %v", err)
+		p.publishPage(events.PageFailed, page)
 		return p.storage.AddPageAnalysis(p.audit.ID, page)
 	}
 
-	// Run SEO checks
-	checker := NewChecker(analysis, pageData.StatusCode)
+	// Run SEO checks, comparing with the renders on other devices and the raw HTML
+	checker := NewChecker(analysis, &pageData)
+	checker.SetBudget(p.Budget)
+	for _, render := range pageData.Renders {
+		if render.Error != "" || render.StatusCode != 200 {
+			continue
+		}
+		if renderAnalysis, err := p.analyzer.AnalyzeContent(render.Content, contentURL); err == nil {
+			checker.AddDeviceRender(render.Device, renderAnalysis)
+		}
+	}
+	if raw := pageData.Raw; raw != nil && raw.Error == "" && raw.StatusCode == 200 {
+		if rawAnalysis, err := p.analyzer.AnalyzeContent(raw.Content, contentURL); err == nil {
+			checker.SetRawAnalysis(rawAnalysis)
+		}
+	}
 	checkResults := checker.RunAllChecks()
 
 	// Populate page data from analysis
@@ -177,7 +303,27 @@ func (p *Processor) processPage(pageData crawler.PageResult) error {
 	log.Printf("✅ Completed analysis for %s (score: %.1f)", pageData.URL, *page.SEOScore)
 
 	// Save page to audit
-	return p.storage.AddPageAnalysis(p.audit.ID, page)
+	if err := p.storage.AddPageAnalysis(p.audit.ID, page); err != nil {
+		return err
+	}
+	p.publishPage(events.PageAnalyzed, page)
+	return nil
+}
+
+// publishPage publishes a page event for a stored page analysis
+func (p *Processor) publishPage(eventType events.Type, page LocalPageAnalysis) {
+	event := events.Event{
+		Type:       eventType,
+		AuditID:    p.audit.ID,
+		URL:        page.URL,
+		Depth:      page.Depth,
+		StatusCode: page.StatusCode,
+		Score:      page.SEOScore,
+	}
+	if eventType == events.PageFailed {
+		event.Error = page.IndexabilityReason
+	}
+	p.OnEvent.Publish(event)
 }
 
 // populatePageFromAnalysis fills page data from analysis results
@@ -227,18 +373,23 @@ func (p *Processor) populatePageFromAnalysis(page *LocalPageAnalysis, analysis *
 	page.DetectedLanguage = analysis.Language
 
 	// Count issues (failed checks)
-	issuesCount := 0
-	for _, check := range checks.Checks {
-		if !check.Passed {
-			issuesCount++
-		}
-	}
-	page.IssuesCount = issuesCount
+	page.IssuesCount = countIssues(checks.Checks)
 
 	// Store check results
 	page.Checks = checks.Checks
 }
 
+// countIssues returns the number of failed checks
+func countIssues(checks map[string]CheckResult) int {
+	issues := 0
+	for _, check := range checks {
+		if !check.Passed {
+			issues++
+		}
+	}
+	return issues
+}
+
 // addFailedPage adds a page that couldn't be crawled
 func (p *Processor) addFailedPage(pageData crawler.PageResult) {
 	page := LocalPageAnalysis{
@@ -246,40 +397,142 @@ func (p *Processor) addFailedPage(pageData crawler.PageResult) {
 		URL:                pageData.URL,
 		StatusCode:         pageData.StatusCode,
 		Depth:              pageData.Depth,
+		Source:             pageData.Source,
+		LinkKind:           pageData.LinkKind,
 		AnalysisStatus:     string(PageStatusFailed),
 		IndexabilityReason: fmt.Sprintf("HTTP %d - Page not accessible", pageData.StatusCode),
 		IsIndexable:        false,
 		IssuesCount:        1,
+		FinalURL:           pageData.FinalURL,
+		RedirectChain:      convertRedirects(pageData.Redirects),
+		RedirectLoop:       pageData.RedirectLoop,
+		Screenshots:        p.convertScreenshots(pageData.Screenshots),
+	}
+
+	if pageData.RedirectLoop {
+		page.IndexabilityReason = "Redirect loop - page never resolves"
+		page.Checks = NewChecker(nil, &pageData).RunFailedPageChecks()
 	}
 
 	score := 0.0
 	page.SEOScore = &score
 
 	p.storage.AddPageAnalysis(p.audit.ID, page)
+	p.publishPage(events.PageFailed, page)
 }
 
-// completeAudit finalizes the audit
-func (p *Processor) completeAudit() {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// convertScreenshots stores screenshot paths relative to the audit directory,
+// so the audit stays valid if ~/.seo is moved
+func (p *Processor) convertScreenshots(shots *crawler.PageScreenshots) *PageScreenshots {
+	if shots == nil {
+		return nil
+	}
 
-	if p.audit == nil {
-		return
+	dir := p.storage.auditDirPath(p.audit.ID)
+	relative := func(path string) string {
+		if path == "" {
+			return ""
+		}
+		if rel, err := filepath.Rel(dir, path); err == nil {
+			return filepath.ToSlash(rel)
+		}
+		return path
 	}
 
-	log.Printf("🏁 Completing audit %s", p.audit.ID)
+	return &PageScreenshots{
+		FullPage:  relative(shots.FullPage),
+		AboveFold: relative(shots.AboveFold),
+		Error:     shots.Error,
+	}
+}
 
-	if err := p.storage.CompleteAudit(p.audit.ID); err != nil {
-		log.Printf("❌ Failed to complete audit: %v", err)
-		return
+// convertRedirects converts crawler redirect hops to their stored form
+func convertRedirects(hops []crawler.RedirectHop) []RedirectHop {
+	if len(hops) == 0 {
+		return nil
+	}
+
+	redirects := make([]RedirectHop, len(hops))
+	for i, hop := range hops {
+		redirects[i] = RedirectHop{
+			URL:        hop.URL,
+			StatusCode: hop.StatusCode,
+			Location:   hop.Location,
+		}
+	}
+	return redirects
+}
+
+// convertConsoleErrors converts crawler console errors to their stored form
+func convertConsoleErrors(entries []crawler.ConsoleError) []ConsoleError {
+	if len(entries) == 0 {
+		return nil
+	}
+	converted := make([]ConsoleError, len(entries))
+	for i, entry := range entries {
+		converted[i] = ConsoleError{Message: entry.Message, Location: entry.Location}
+	}
+	return converted
+}
+
+// convertFailedRequests converts crawler failed requests to their stored form
+func convertFailedRequests(entries []crawler.FailedRequest) []FailedRequest {
+	if len(entries) == 0 {
+		return nil
+	}
+	converted := make([]FailedRequest, len(entries))
+	for i, entry := range entries {
+		converted[i] = FailedRequest{
+			URL:          entry.URL,
+			ResourceType: entry.ResourceType,
+			StatusCode:   entry.StatusCode,
+			Error:        entry.Error,
+		}
+	}
+	return converted
+}
+
+// filterHeaders keeps only the response headers listed in storedHeaders
+func filterHeaders(headers map[string]string) map[string]string {
+	filtered := make(map[string]string)
+	for _, name := range storedHeaders {
+		if value, ok := headers[name]; ok {
+			filtered[name] = value
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// CompleteAudit finalizes the audit once every page has been analyzed. Use
+// StatusPartial for an audit whose crawl was interrupted.
+func (p *Processor) CompleteAudit(auditID string, status ProcessorStatus) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	log.Printf("🏁 Completing audit %s (%s)", auditID, status)
+
+	if err := p.storage.CompleteAudit(auditID, string(status)); err != nil {
+		return fmt.Errorf("failed to complete audit: %w", err)
 	}
 
 	// Reload audit to get updated data
-	if updatedAudit, err := p.storage.LoadAudit(p.audit.ID); err == nil {
-		p.audit = updatedAudit
+	updatedAudit, err := p.storage.LoadAudit(auditID)
+	if err != nil {
+		return err
 	}
+	p.audit = updatedAudit
 
 	log.Printf("✅ Audit completed: %d pages analyzed", p.audit.PagesAnalyzed)
+	p.OnEvent.Publish(events.Event{
+		Type:    events.AuditCompleted,
+		AuditID: auditID,
+		Score:   p.audit.OverallScore,
+		Partial: status == StatusPartial,
+	})
+	return nil
 }
 
 // GetAuditStatus returns current audit status and progress
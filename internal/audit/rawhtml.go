@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SetRawAnalysis adds the analysis of the page's raw HTML, before JavaScript
+// ran, which RunAllChecks compares with the rendered page
+func (c *Checker) SetRawAnalysis(analysis *AnalysisResult) {
+	c.raw = analysis
+}
+
+// runRawHTMLChecks flags SEO content that only appears, or changes, once
+// JavaScript has run; crawlers that don't render see the raw values
+func (c *Checker) runRawHTMLChecks() {
+	c.compareRawValue("js_title", "Title", c.raw.Title, c.analysis.Title)
+	c.compareRawValue("js_meta_description", "Meta description", c.raw.Description, c.analysis.Description)
+	c.compareRawValue("js_canonical", "Canonical URL", c.raw.Technical.Canonical, c.analysis.Technical.Canonical)
+	c.compareRawValue("js_robots_meta", "Robots meta", describeRobots(c.raw.Robots), describeRobots(c.analysis.Robots))
+	c.compareRawValue("js_h1", "H1", strings.Join(c.raw.H1, " | "), strings.Join(c.analysis.H1, " | "))
+	c.checkRawInternalLinks()
+	c.checkRawWordCount()
+}
+
+// compareRawValue records whether a value is the same in the raw and rendered HTML
+func (c *Checker) compareRawValue(name, label, raw, rendered string) {
+	raw = strings.TrimSpace(raw)
+	rendered = strings.TrimSpace(rendered)
+
+	passed := raw == rendered
+	var message string
+	switch {
+	case passed && raw == "":
+		message = fmt.Sprintf("%s is missing from both the raw and rendered HTML", label)
+	case passed:
+		message = fmt.Sprintf("%s is served in the raw HTML", label)
+	case raw == "":
+		message = fmt.Sprintf("%s is only added by JavaScript (rendered: %q)", label, rendered)
+	case rendered == "":
+		message = fmt.Sprintf("%s in the raw HTML is removed by JavaScript (raw: %q)", label, raw)
+	default:
+		message = fmt.Sprintf("%s is changed by JavaScript (raw: %q, rendered: %q)", label, raw, rendered)
+	}
+
+	c.results[name] = CheckResult{
+		Passed:  passed,
+		Value:   rendered,
+		Message: message,
+		Weight:  c.weights[name],
+	}
+}
+
+// describeRobots renders robots meta directives for comparison
+func describeRobots(robots *RobotsData) string {
+	directives := []string{"index", "follow"}
+	if robots.NoIndex {
+		directives[0] = "noindex"
+	}
+	if robots.NoFollow {
+		directives[1] = "nofollow"
+	}
+	return strings.Join(directives, ", ")
+}
+
+func (c *Checker) checkRawInternalLinks() {
+	inRaw := make(map[string]bool, len(c.raw.Links.Internal))
+	for _, link := range c.raw.Links.Internal {
+		inRaw[link.URL] = true
+	}
+
+	missingSet := make(map[string]bool)
+	for _, link := range c.analysis.Links.Internal {
+		if !inRaw[link.URL] {
+			missingSet[link.URL] = true
+		}
+	}
+	missing := make([]string, 0, len(missingSet))
+	for u := range missingSet {
+		missing = append(missing, u)
+	}
+	sort.Strings(missing)
+
+	passed := len(missing) == 0
+	message := "Every internal link is in the raw HTML"
+	if !passed {
+		message = fmt.Sprintf("%d internal links are only added by JavaScript: %s",
+			len(missing), joinLimited(missing, maxListedProblems))
+	}
+
+	c.results["js_internal_links"] = CheckResult{
+		Passed:  passed,
+		Value:   len(missing),
+		Message: message,
+		Weight:  c.weights["js_internal_links"],
+	}
+}
+
+func (c *Checker) checkRawWordCount() {
+	rawWords := c.raw.Content.WordCount
+	words := c.analysis.Content.WordCount
+
+	ratio := 1.0
+	if words > 0 {
+		ratio = float64(rawWords) / float64(words)
+	}
+
+	passed := ratio >= minContentParity
+	message := fmt.Sprintf("The raw HTML has the page's content (%d of %d words)", rawWords, words)
+	if !passed {
+		message = fmt.Sprintf("Most of the page's content is only rendered by JavaScript (%d of %d words in the raw HTML)", rawWords, words)
+	}
+
+	c.results["js_word_count"] = CheckResult{
+		Passed:  passed,
+		Value:   fmt.Sprintf("%.0f%%", ratio*100),
+		Message: message,
+		Weight:  c.weights["js_word_count"],
+	}
+}
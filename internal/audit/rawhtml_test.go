@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ugolbck/seofordev/internal/crawler"
+)
+
+// htmlPage builds an HTML document for raw-vs-rendered tests
+func htmlPage(head, body string) string {
+	return "<!DOCTYPE html><html><head>" + head + "</head><body>" + body + "</body></html>"
+}
+
+func TestRawHTMLChecks(t *testing.T) {
+	const words = "<p>one two three four five six seven eight nine ten</p>"
+	const head = `<title>Pricing</title><meta name="description" content="Plans and prices">` +
+		`<link rel="canonical" href="http://localhost:3000/pricing">`
+
+	tests := []struct {
+		name        string
+		raw         string
+		rendered    string
+		check       string
+		wantPassed  bool
+		wantMessage string
+	}{
+		{
+			name:        "title served",
+			raw:         htmlPage(head, words),
+			rendered:    htmlPage(head, words),
+			check:       "js_title",
+			wantPassed:  true,
+			wantMessage: "Title is served in the raw HTML",
+		},
+		{
+			name:        "title added",
+			raw:         htmlPage("", words),
+			rendered:    htmlPage(head, words),
+			check:       "js_title",
+			wantPassed:  false,
+			wantMessage: `Title is only added by JavaScript (rendered: "Pricing")`,
+		},
+		{
+			name:        "title changed",
+			raw:         htmlPage("<title>Loading...</title>", words),
+			rendered:    htmlPage(head, words),
+			check:       "js_title",
+			wantPassed:  false,
+			wantMessage: `Title is changed by JavaScript (raw: "Loading...", rendered: "Pricing")`,
+		},
+		{
+			name:        "description missing everywhere",
+			raw:         htmlPage("<title>Pricing</title>", words),
+			rendered:    htmlPage("<title>Pricing</title>", words),
+			check:       "js_meta_description",
+			wantPassed:  true,
+			wantMessage: "Meta description is missing from both the raw and rendered HTML",
+		},
+		{
+			name:        "canonical removed",
+			raw:         htmlPage(head, words),
+			rendered:    htmlPage("<title>Pricing</title>", words),
+			check:       "js_canonical",
+			wantPassed:  false,
+			wantMessage: `Canonical URL in the raw HTML is removed by JavaScript (raw: "http://localhost:3000/pricing")`,
+		},
+		{
+			name:        "noindex added",
+			raw:         htmlPage(head, words),
+			rendered:    htmlPage(head+`<meta name="robots" content="noindex">`, words),
+			check:       "js_robots_meta",
+			wantPassed:  false,
+			wantMessage: `Robots meta is changed by JavaScript (raw: "index, follow", rendered: "noindex, follow")`,
+		},
+		{
+			name:        "h1 added",
+			raw:         htmlPage(head, words),
+			rendered:    htmlPage(head, "<h1>Plans</h1>"+words),
+			check:       "js_h1",
+			wantPassed:  false,
+			wantMessage: `H1 is only added by JavaScript (rendered: "Plans")`,
+		},
+		{
+			name:        "links in the raw HTML",
+			raw:         htmlPage(head, `<a href="/a">A</a><a href="/b">B</a>`+words),
+			rendered:    htmlPage(head, `<a href="/b">B</a><a href="/a">A</a>`+words),
+			check:       "js_internal_links",
+			wantPassed:  true,
+			wantMessage: "Every internal link is in the raw HTML",
+		},
+		{
+			name:        "links added",
+			raw:         htmlPage(head, `<a href="/a">A</a>`+words),
+			rendered:    htmlPage(head, `<a href="/a">A</a><a href="/d">D</a><a href="/c">C</a><a href="/c">C</a><a href="https://example.com/">Out</a>`+words),
+			check:       "js_internal_links",
+			wantPassed:  false,
+			wantMessage: "2 internal links are only added by JavaScript: http://localhost:3000/c; http://localhost:3000/d",
+		},
+		{
+			name:        "content in the raw HTML",
+			raw:         htmlPage(head, words),
+			rendered:    htmlPage(head, "<p>one two three four five six seven eight nine ten eleven</p>"),
+			check:       "js_word_count",
+			wantPassed:  true,
+			wantMessage: "The raw HTML has the page's content (10 of 11 words)",
+		},
+		{
+			name:        "content rendered by JavaScript",
+			raw:         htmlPage(head, `<div id="root"></div>`),
+			rendered:    htmlPage(head, words),
+			check:       "js_word_count",
+			wantPassed:  false,
+			wantMessage: "Most of the page's content is only rendered by JavaScript (0 of 10 words in the raw HTML)",
+		},
+		{
+			name:        "empty page",
+			raw:         htmlPage(head, ""),
+			rendered:    htmlPage(head, ""),
+			check:       "js_word_count",
+			wantPassed:  true,
+			wantMessage: "(0 of 0 words)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &crawler.PageResult{URL: "http://localhost:3000/", StatusCode: 200}
+			checker := NewChecker(analyze(t, tt.rendered), page)
+			checker.SetRawAnalysis(analyze(t, tt.raw))
+			checker.runRawHTMLChecks()
+
+			result, ok := checker.results[tt.check]
+			if !ok {
+				t.Fatalf("%s was not checked", tt.check)
+			}
+			if result.Passed != tt.wantPassed {
+				t.Errorf("%s passed = %v, want %v (%s)", tt.check, result.Passed, tt.wantPassed, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("%s message = %q, want it to contain %q", tt.check, result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestDescribeRobots(t *testing.T) {
+	tests := []struct {
+		robots RobotsData
+		want   string
+	}{
+		{RobotsData{}, "index, follow"},
+		{RobotsData{NoIndex: true}, "noindex, follow"},
+		{RobotsData{NoFollow: true}, "index, nofollow"},
+		{RobotsData{NoIndex: true, NoFollow: true, NoCache: true}, "noindex, nofollow"},
+	}
+
+	for _, tt := range tests {
+		if got := describeRobots(&tt.robots); got != tt.want {
+			t.Errorf("describeRobots(%+v) = %q, want %q", tt.robots, got, tt.want)
+		}
+	}
+}
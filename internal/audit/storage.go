@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ugolbck/seofordev/internal/urlnorm"
 )
 
 // LocalAudit represents a complete audit stored locally
@@ -26,15 +27,44 @@ type LocalAudit struct {
 	AvgPageScore     *float64                `json:"avg_page_score,omitempty"`
 	Config           AuditConfig             `json:"config"`
 	Pages            []LocalPageAnalysis     `json:"pages"`
+	Sitemap          *SitemapCoverage        `json:"sitemap,omitempty"`
+	Crawl            *CrawlStats             `json:"crawl,omitempty"`
 	Summary          *LocalAuditSummary      `json:"summary,omitempty"`
 }
 
+// CrawlStats records how much of the discovered site was crawled
+type CrawlStats struct {
+	PagesCrawled    int          `json:"pages_crawled"`
+	NotCrawled      int          `json:"not_crawled,omitempty"` // Discovered but skipped because of the page limit
+	DurationSeconds float64      `json:"duration_seconds,omitempty"`
+	PagesPerSecond  float64      `json:"pages_per_second,omitempty"`
+	CrawlDelay      float64      `json:"crawl_delay,omitempty"` // Seconds, from robots.txt
+	Throttled       int          `json:"throttled,omitempty"`   // 429/503 responses that were retried
+	Skipped         []SkippedURL `json:"skipped,omitempty"`     // Same-host URLs that were not crawled
+}
+
+// SkippedURL records why a discovered URL was not audited
+type SkippedURL struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"` // ignored, robots, out-of-scope or depth
+}
+
+// SitemapCoverage compares sitemap entries with the site's internal linking
+type SitemapCoverage struct {
+	SitemapURLs  int      `json:"sitemap_urls"`
+	Unlinked     []string `json:"unlinked,omitempty"`
+	NotInSitemap []string `json:"not_in_sitemap,omitempty"`
+}
+
 // LocalPageAnalysis represents a single page's SEO analysis
 type LocalPageAnalysis struct {
 	ID             string                  `json:"id"`
 	URL            string                  `json:"url"`
 	StatusCode     int                     `json:"status_code"`
 	Depth          int                     `json:"depth"`
+	Source         string                  `json:"source,omitempty"`
+	LinkKind       string                  `json:"link_kind,omitempty"`
+	Device         string                  `json:"device,omitempty"`
 	AnalysisStatus string                  `json:"analysis_status"`
 	SEOScore       *float64                `json:"seo_score,omitempty"`
 	AnalyzedAt     *time.Time              `json:"analyzed_at,omitempty"`
@@ -68,6 +98,54 @@ type LocalPageAnalysis struct {
 	
 	// Links (for internal link analysis)
 	InternalLinks []LinkInfo `json:"internal_links,omitempty"`
+
+	// Redirects followed before reaching the page content
+	FinalURL      string        `json:"final_url,omitempty"`
+	RedirectChain []RedirectHop `json:"redirect_chain,omitempty"`
+	RedirectLoop  bool          `json:"redirect_loop,omitempty"`
+
+	// SEO-relevant HTTP response headers
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+
+	// JavaScript errors and broken assets seen while rendering (browser renderer)
+	ConsoleErrors  []ConsoleError  `json:"console_errors,omitempty"`
+	PageErrors     []string        `json:"page_errors,omitempty"`
+	FailedRequests []FailedRequest `json:"failed_requests,omitempty"`
+
+	// Loading metrics measured in the browser
+	Performance *PerformanceMetrics `json:"performance,omitempty"`
+
+	// PNG captures of the rendered page (--screenshots)
+	Screenshots *PageScreenshots `json:"screenshots,omitempty"`
+}
+
+// PageScreenshots links a page to its captures. Paths are relative to the
+// audit's directory.
+type PageScreenshots struct {
+	FullPage  string `json:"full_page,omitempty"`
+	AboveFold string `json:"above_fold,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ConsoleError represents a console.error message logged by a page
+type ConsoleError struct {
+	Message  string `json:"message"`
+	Location string `json:"location,omitempty"`
+}
+
+// FailedRequest represents a subresource that failed to load
+type FailedRequest struct {
+	URL          string `json:"url"`
+	ResourceType string `json:"resource_type"`
+	StatusCode   int    `json:"status_code,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RedirectHop represents one redirect response in a chain
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Location   string `json:"location"`
 }
 
 // LocalAuditSummary represents audit summary statistics
@@ -92,15 +170,57 @@ type LocalAuditSummary struct {
 	DuplicateTitlesCount   int       `json:"duplicate_titles_count"`
 	DuplicateDescriptionsCount int   `json:"duplicate_descriptions_count"`
 	OrphanedPagesCount     int       `json:"orphaned_pages_count"`
+	SitemapUnlinkedCount   int       `json:"sitemap_unlinked_count"`
+	MissingFromSitemapCount int      `json:"missing_from_sitemap_count"`
+	RedirectingPagesCount  int       `json:"redirecting_pages_count"`
+	PagesLinkingToRedirects int      `json:"pages_linking_to_redirects"`
 }
 
 // AuditConfig represents audit configuration
 type AuditConfig struct {
 	Port           int      `json:"port"`
+	URL            string   `json:"url,omitempty"`
 	Concurrency    int      `json:"concurrency"`
 	MaxPages       int      `json:"max_pages"`
 	MaxDepth       int      `json:"max_depth"`
 	IgnorePatterns []string `json:"ignore_patterns"`
+	IncludePatterns []string `json:"include_patterns,omitempty"`
+	ListMode       bool     `json:"list_mode,omitempty"` // Only the listed URLs were audited
+	URLs           []string `json:"urls,omitempty"`
+	Renderer       string   `json:"renderer,omitempty"`
+	Insecure       bool     `json:"insecure,omitempty"`
+	BlockResources []string `json:"block_resources,omitempty"`
+	Rate           float64  `json:"rate,omitempty"`
+	RobotsAgent    string   `json:"robots_agent,omitempty"`
+	Profile        string   `json:"profile,omitempty"`
+	Device         string   `json:"device,omitempty"`
+	Screenshots    bool     `json:"screenshots,omitempty"`
+	CompareRaw     bool     `json:"compare_raw,omitempty"`
+	RobotsFailOpen bool     `json:"robots_fail_open,omitempty"`
+	Budget         *PerformanceBudget `json:"budget,omitempty"`
+	URLPolicy      *urlnorm.Policy `json:"url_policy,omitempty"`
+	Auth           *AuthSummary `json:"auth,omitempty"`
+	Exec           string   `json:"exec,omitempty"`         // Dev server command run for the audit
+	WaitFor        string   `json:"wait_for,omitempty"`
+	WaitTimeout    float64  `json:"wait_timeout,omitempty"` // Seconds
+}
+
+// urlPolicy returns the URL policy the audit was crawled with; audits saved
+// before policies were recorded used the defaults
+func (c AuditConfig) urlPolicy() urlnorm.Policy {
+	if c.URLPolicy == nil {
+		return urlnorm.Policy{}
+	}
+	return *c.URLPolicy
+}
+
+// AuthSummary records how an audit authenticated, without storing secrets
+type AuthSummary struct {
+	HeaderNames   []string `json:"header_names,omitempty"`
+	BasicAuthUser string   `json:"basic_auth_user,omitempty"`
+	CookieFile    string   `json:"cookie_file,omitempty"`
+	StorageState  string   `json:"storage_state,omitempty"`
+	LoginScript   string   `json:"login_script,omitempty"`
 }
 
 // LocalStorage handles local audit storage
@@ -224,9 +344,27 @@ func (s *LocalStorage) DeleteAudit(auditID string) error {
 		return fmt.Errorf("failed to delete audit file: %w", err)
 	}
 
+	// Remove checkpoints and other per-audit files
+	if err := os.RemoveAll(s.auditDirPath(auditID)); err != nil {
+		return fmt.Errorf("failed to delete audit directory: %w", err)
+	}
+
 	return nil
 }
 
+// AuditDir returns the directory holding an audit's auxiliary files, creating it if needed
+func (s *LocalStorage) AuditDir(auditID string) (string, error) {
+	dir := s.auditDirPath(auditID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	return dir, nil
+}
+
+func (s *LocalStorage) auditDirPath(auditID string) string {
+	return filepath.Join(s.baseDir, auditID)
+}
+
 // AddPageAnalysis adds a page analysis to an audit atomically
 func (s *LocalStorage) AddPageAnalysis(auditID string, page LocalPageAnalysis) error {
 	s.fileMutex.Lock()
@@ -291,8 +429,45 @@ func (s *LocalStorage) AddPageAnalysis(auditID string, page LocalPageAnalysis) e
 	return nil
 }
 
-// CompleteAudit marks an audit as completed and generates summary
-func (s *LocalStorage) CompleteAudit(auditID string) error {
+// UpdateStatus changes the status of an audit
+func (s *LocalStorage) UpdateStatus(auditID string, status string) error {
+	audit, err := s.LoadAudit(auditID)
+	if err != nil {
+		return fmt.Errorf("failed to load audit: %w", err)
+	}
+
+	audit.Status = status
+
+	return s.SaveAudit(audit)
+}
+
+// UpdateSitemapCoverage stores the sitemap coverage found while crawling
+func (s *LocalStorage) UpdateSitemapCoverage(auditID string, coverage SitemapCoverage) error {
+	audit, err := s.LoadAudit(auditID)
+	if err != nil {
+		return fmt.Errorf("failed to load audit: %w", err)
+	}
+
+	audit.Sitemap = &coverage
+
+	return s.SaveAudit(audit)
+}
+
+// UpdateCrawlStats stores the statistics of the crawl
+func (s *LocalStorage) UpdateCrawlStats(auditID string, stats CrawlStats) error {
+	audit, err := s.LoadAudit(auditID)
+	if err != nil {
+		return fmt.Errorf("failed to load audit: %w", err)
+	}
+
+	audit.Crawl = &stats
+
+	return s.SaveAudit(audit)
+}
+
+// CompleteAudit finalizes an audit with the given status ("completed" or "partial")
+// and generates its summary
+func (s *LocalStorage) CompleteAudit(auditID string, status string) error {
 	audit, err := s.LoadAudit(auditID)
 	if err != nil {
 		return fmt.Errorf("failed to load audit: %w", err)
@@ -300,7 +475,10 @@ func (s *LocalStorage) CompleteAudit(auditID string) error {
 
 	now := time.Now()
 	audit.CompletedAt = &now
-	audit.Status = "completed"
+	audit.Status = status
+
+	// Cross-page checks need every page to be analyzed first
+	applyRedirectLinkChecks(audit)
 
 	// Generate summary
 	audit.Summary = s.generateSummary(audit)
@@ -309,6 +487,85 @@ func (s *LocalStorage) CompleteAudit(auditID string) error {
 	return s.SaveAudit(audit)
 }
 
+// applyRedirectLinkChecks flags pages whose internal links point at redirecting URLs,
+// and at temporary redirects in particular. Both checks belong to the linking page,
+// which is where the link has to be fixed.
+// Noindex pages are checked too: crawlers still follow their links, so each one is
+// a wasted hop. Their score stays 0, as non-indexable pages are not scored.
+func applyRedirectLinkChecks(audit *LocalAudit) {
+	policy := audit.Config.urlPolicy()
+	redirects := make(map[string][]RedirectHop)
+	for _, page := range audit.Pages {
+		if len(page.RedirectChain) > 0 {
+			redirects[page.URL] = page.RedirectChain
+		}
+	}
+
+	for i := range audit.Pages {
+		page := &audit.Pages[i]
+		if page.AnalysisStatus != string(PageStatusCompleted) {
+			continue // Page was not analyzed
+		}
+		if page.Checks == nil {
+			page.Checks = make(map[string]CheckResult)
+		}
+
+		var targets, temporary []string
+		for _, link := range page.InternalLinks {
+			chain, ok := redirects[policy.Normalize(link.URL)]
+			if !ok {
+				continue
+			}
+			targets = append(targets, link.URL)
+			for _, hop := range chain {
+				if isTemporaryRedirect(hop.StatusCode) {
+					temporary = append(temporary, fmt.Sprintf("%s (%d)", link.URL, hop.StatusCode))
+					break
+				}
+			}
+		}
+
+		passed := len(targets) == 0
+		message := "Internal links point directly at final URLs"
+		if !passed {
+			message = fmt.Sprintf("%d internal links point at redirecting URLs (%s)", len(targets), strings.Join(targets, ", "))
+		}
+		page.Checks["internal_links_to_redirects"] = CheckResult{
+			Passed:  passed,
+			Value:   len(targets),
+			Message: message,
+			Weight:  checkWeights["internal_links_to_redirects"],
+		}
+
+		passed = len(temporary) == 0
+		message = "No internal link goes through a temporary redirect"
+		if !passed {
+			message = fmt.Sprintf("%d internal links go through temporary redirects (%s) - link to the final URL, or make the redirect a permanent 301/308",
+				len(temporary), strings.Join(temporary, ", "))
+		}
+		page.Checks["temporary_redirect"] = CheckResult{
+			Passed:  passed,
+			Value:   len(temporary),
+			Message: message,
+			Weight:  checkWeights["temporary_redirect"],
+		}
+
+		// Refresh the score and issue count to include the new checks. Both are
+		// recomputed from the checks, as a resumed audit is completed again.
+		if page.IsIndexable {
+			score := calculateScore(page.Checks)
+			page.SEOScore = &score
+		}
+		page.IssuesCount = countIssues(page.Checks)
+	}
+}
+
+// isTemporaryRedirect reports whether a redirect status asks clients to keep using
+// the original URL
+func isTemporaryRedirect(statusCode int) bool {
+	return statusCode == 302 || statusCode == 303 || statusCode == 307
+}
+
 // generateSummary generates audit summary statistics
 func (s *LocalStorage) generateSummary(audit *LocalAudit) *LocalAuditSummary {
 	summary := &LocalAuditSummary{
@@ -330,9 +587,12 @@ func (s *LocalStorage) generateSummary(audit *LocalAudit) *LocalAuditSummary {
 	passedChecks := 0
 	failedChecks := 0
 
-	// Track duplicates
+	// Track duplicates. Crawled URLs that canonicalize to the same page, or
+	// redirect to it, are counted once.
+	policy := audit.Config.urlPolicy()
 	titles := make(map[string]int)
 	descriptions := make(map[string]int)
+	countedPages := make(map[string]bool)
 
 	for _, page := range audit.Pages {
 		if page.SEOScore != nil {
@@ -354,18 +614,34 @@ func (s *LocalStorage) generateSummary(audit *LocalAudit) *LocalAuditSummary {
 			}
 		}
 
+		// Count redirects
+		if len(page.RedirectChain) > 0 {
+			summary.RedirectingPagesCount++
+		}
+		if check, ok := page.Checks["internal_links_to_redirects"]; ok && !check.Passed {
+			summary.PagesLinkingToRedirects++
+		}
+
 		// Count missing elements
+		pageKey := page.URL
+		if page.FinalURL != "" {
+			pageKey = page.FinalURL
+		}
+		pageKey = policy.Normalize(pageKey)
+		firstVisit := !countedPages[pageKey]
+		countedPages[pageKey] = true
+
 		if strings.TrimSpace(page.Title) == "" {
 			summary.PagesMissingTitle++
 			issueTracker["Missing title tag"]++
-		} else {
+		} else if firstVisit {
 			titles[page.Title]++
 		}
 
 		if strings.TrimSpace(page.MetaDescription) == "" {
 			summary.PagesMissingDescription++
 			issueTracker["Missing meta description"]++
-		} else {
+		} else if firstVisit {
 			descriptions[page.MetaDescription]++
 		}
 
@@ -401,6 +677,12 @@ func (s *LocalStorage) generateSummary(audit *LocalAudit) *LocalAuditSummary {
 	summary.PassedChecks = passedChecks
 	summary.FailedChecks = failedChecks
 
+	// Sitemap coverage
+	if audit.Sitemap != nil {
+		summary.SitemapUnlinkedCount = len(audit.Sitemap.Unlinked)
+		summary.MissingFromSitemapCount = len(audit.Sitemap.NotInSitemap)
+	}
+
 	// Count duplicates
 	for _, count := range titles {
 		if count > 1 {
@@ -466,6 +748,18 @@ func (s *LocalStorage) generateRecommendations(summary *LocalAuditSummary, issue
 		recommendations = append(recommendations, fmt.Sprintf("Fix %d duplicate meta descriptions", summary.DuplicateDescriptionsCount))
 	}
 
+	if summary.PagesLinkingToRedirects > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("Update internal links on %d pages to point at final URLs instead of redirects", summary.PagesLinkingToRedirects))
+	}
+
+	if summary.SitemapUnlinkedCount > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("Link to %d sitemap pages that no page links to", summary.SitemapUnlinkedCount))
+	}
+
+	if summary.MissingFromSitemapCount > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("Add %d linked pages to your sitemap", summary.MissingFromSitemapCount))
+	}
+
 	if issues["Page is missing viewport meta tag (important for mobile)"] > 0 {
 		recommendations = append(recommendations, "Add viewport meta tag for mobile optimization")
 	}
@@ -0,0 +1,126 @@
+package audit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyRedirectLinkChecks(t *testing.T) {
+	page := func(url string, links ...string) LocalPageAnalysis {
+		p := LocalPageAnalysis{URL: url, AnalysisStatus: string(PageStatusCompleted), IsIndexable: true}
+		for _, link := range links {
+			p.InternalLinks = append(p.InternalLinks, LinkInfo{URL: link})
+		}
+		return p
+	}
+
+	audit := &LocalAudit{Pages: []LocalPageAnalysis{
+		page("http://localhost:3000/", "http://localhost:3000/old", "http://localhost:3000/moved", "http://localhost:3000/about"),
+		page("http://localhost:3000/about", "http://localhost:3000/#top"),
+		page("http://localhost:3000/blog", "http://localhost:3000/moved/"), // Normalized before lookup
+		{
+			// Reached from the seed through a temporary redirect; no page links to it
+			URL:            "http://localhost:3000/start",
+			AnalysisStatus: string(PageStatusCompleted),
+			IsIndexable:    true,
+			RedirectChain:  []RedirectHop{{URL: "http://localhost:3000/start", StatusCode: 302}},
+		},
+		{
+			URL:            "http://localhost:3000/old",
+			AnalysisStatus: string(PageStatusCompleted),
+			IsIndexable:    true,
+			RedirectChain:  []RedirectHop{{URL: "http://localhost:3000/old", StatusCode: 301}},
+		},
+		{
+			URL:            "http://localhost:3000/moved",
+			AnalysisStatus: string(PageStatusCompleted),
+			IsIndexable:    true,
+			RedirectChain: []RedirectHop{
+				{URL: "http://localhost:3000/moved", StatusCode: 301},
+				{URL: "http://localhost:3000/moved-again", StatusCode: 307},
+			},
+		},
+		{URL: "http://localhost:3000/broken", AnalysisStatus: string(PageStatusFailed), InternalLinks: []LinkInfo{{URL: "http://localhost:3000/old"}}},
+	}}
+
+	tests := []struct {
+		url           string
+		redirects     int
+		temporary     int
+		wantTemporary string // Substring of the temporary_redirect message
+	}{
+		{"http://localhost:3000/", 2, 1, "http://localhost:3000/moved (307)"},
+		{"http://localhost:3000/about", 0, 0, "No internal link"},
+		{"http://localhost:3000/blog", 1, 1, "http://localhost:3000/moved/ (307)"},
+		{"http://localhost:3000/start", 0, 0, "No internal link"},
+	}
+
+	// Completing a resumed audit applies the checks again; nothing may change
+	for run := 1; run <= 2; run++ {
+		applyRedirectLinkChecks(audit)
+
+		for _, tt := range tests {
+			p := findPage(t, audit, tt.url)
+			redirects := p.Checks["internal_links_to_redirects"]
+			if redirects.Value != tt.redirects || redirects.Passed != (tt.redirects == 0) {
+				t.Errorf("run %d: %s internal_links_to_redirects = %v (passed %v), want %d", run, tt.url, redirects.Value, redirects.Passed, tt.redirects)
+			}
+			temporary := p.Checks["temporary_redirect"]
+			if temporary.Value != tt.temporary || temporary.Passed != (tt.temporary == 0) {
+				t.Errorf("run %d: %s temporary_redirect = %v (passed %v), want %d", run, tt.url, temporary.Value, temporary.Passed, tt.temporary)
+			}
+			if !strings.Contains(temporary.Message, tt.wantTemporary) {
+				t.Errorf("run %d: %s temporary_redirect message = %q, want it to contain %q", run, tt.url, temporary.Message, tt.wantTemporary)
+			}
+			if p.IssuesCount != countIssues(p.Checks) {
+				t.Errorf("run %d: %s IssuesCount = %d, want %d", run, tt.url, p.IssuesCount, countIssues(p.Checks))
+			}
+		}
+	}
+
+	if broken := findPage(t, audit, "http://localhost:3000/broken"); broken.Checks != nil {
+		t.Errorf("a page that was not analyzed got checks: %v", broken.Checks)
+	}
+}
+
+func TestApplyRedirectLinkChecksNoindex(t *testing.T) {
+	zero := 0.0
+	audit := &LocalAudit{Pages: []LocalPageAnalysis{
+		{
+			URL:            "http://localhost:3000/private",
+			AnalysisStatus: string(PageStatusCompleted),
+			HasNoindex:     true,
+			SEOScore:       &zero,
+			InternalLinks:  []LinkInfo{{URL: "http://localhost:3000/old"}},
+		},
+		{
+			URL:            "http://localhost:3000/old",
+			AnalysisStatus: string(PageStatusCompleted),
+			RedirectChain:  []RedirectHop{{URL: "http://localhost:3000/old", StatusCode: 302}},
+		},
+	}}
+
+	applyRedirectLinkChecks(audit)
+
+	private := findPage(t, audit, "http://localhost:3000/private")
+	if private.Checks["internal_links_to_redirects"].Passed || private.Checks["temporary_redirect"].Passed {
+		t.Error("links on noindex pages should be checked")
+	}
+	if *private.SEOScore != 0 {
+		t.Errorf("noindex page score = %v, want it to stay 0", *private.SEOScore)
+	}
+	if private.IssuesCount != 2 {
+		t.Errorf("IssuesCount = %d, want 2", private.IssuesCount)
+	}
+}
+
+func findPage(t *testing.T, audit *LocalAudit, url string) *LocalPageAnalysis {
+	t.Helper()
+	for i := range audit.Pages {
+		if audit.Pages[i].URL == url {
+			return &audit.Pages[i]
+		}
+	}
+	t.Fatalf("no page %s", url)
+	return nil
+}
@@ -9,11 +9,45 @@ import (
 
 // Config represents the user's local configuration
 type Config struct {
-	DefaultPort           int      `yaml:"default_port" json:"default_port"`
-	DefaultConcurrency    int      `yaml:"default_concurrency" json:"default_concurrency"`
-	DefaultMaxPages       int      `yaml:"default_max_pages" json:"default_max_pages"`
-	DefaultMaxDepth       int      `yaml:"default_max_depth" json:"default_max_depth"`
-	DefaultIgnorePatterns []string `yaml:"default_ignore_patterns" json:"default_ignore_patterns"`
+	DefaultPort           int          `yaml:"default_port" json:"default_port"`
+	DefaultConcurrency    int          `yaml:"default_concurrency" json:"default_concurrency"`
+	DefaultMaxPages       int          `yaml:"default_max_pages" json:"default_max_pages"`
+	DefaultMaxDepth       int          `yaml:"default_max_depth" json:"default_max_depth"`
+	DefaultIgnorePatterns []string     `yaml:"default_ignore_patterns" json:"default_ignore_patterns"`
+	IncludePatterns       []string     `yaml:"include_patterns,omitempty" json:"include_patterns,omitempty"` // Only audit matching URLs (/regex/, globs or substrings)
+	ExcludePatterns       []string     `yaml:"exclude_patterns,omitempty" json:"exclude_patterns,omitempty"` // Never audit matching URLs, on top of --ignore
+	Auth                  AuthConfig   `yaml:"auth,omitempty" json:"auth,omitempty"`
+	Budgets               BudgetConfig `yaml:"budgets,omitempty" json:"budgets,omitempty"`
+	URLNormalization      URLConfig    `yaml:"url_normalization,omitempty" json:"url_normalization,omitempty"`
+}
+
+// URLConfig sets how URLs are canonicalized, so variants of a page are crawled once.
+// Tracking parameters are removed and query parameters sorted unless disabled.
+type URLConfig struct {
+	StripParams     []string `yaml:"strip_params,omitempty" json:"strip_params,omitempty"`                     // Extra query parameters to ignore; a trailing * matches a prefix
+	KeepTracking    bool     `yaml:"keep_tracking_params,omitempty" json:"keep_tracking_params,omitempty"`     // Keep utm_*, gclid, fbclid, ...
+	KeepQueryOrder  bool     `yaml:"keep_query_order,omitempty" json:"keep_query_order,omitempty"`             // Don't sort query parameters
+	CaseInsensitive bool     `yaml:"case_insensitive_paths,omitempty" json:"case_insensitive_paths,omitempty"` // Lowercase paths
+	FoldIndex       bool     `yaml:"fold_index,omitempty" json:"fold_index,omitempty"`                         // Treat /dir/index.html as /dir
+}
+
+// BudgetConfig sets the performance budget pages are checked against.
+// Unset limits use the built-in defaults; 0 is a limit like any other.
+type BudgetConfig struct {
+	TTFBMs     *int     `yaml:"ttfb_ms,omitempty" json:"ttfb_ms,omitempty"`         // Time to first byte
+	LCPMs      *int     `yaml:"lcp_ms,omitempty" json:"lcp_ms,omitempty"`           // Largest Contentful Paint
+	CLS        *float64 `yaml:"cls,omitempty" json:"cls,omitempty"`                 // Cumulative Layout Shift
+	TransferKB *int     `yaml:"transfer_kb,omitempty" json:"transfer_kb,omitempty"` // Total bytes transferred per page
+	Requests   *int     `yaml:"requests,omitempty" json:"requests,omitempty"`       // Requests per page
+}
+
+// AuthConfig holds credentials used to crawl pages behind a login
+type AuthConfig struct {
+	Headers      map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`             // Extra HTTP headers sent with requests to the audited host
+	BasicAuth    string            `yaml:"basic_auth,omitempty" json:"basic_auth,omitempty"`       // "user:password"
+	CookieFile   string            `yaml:"cookie_file,omitempty" json:"cookie_file,omitempty"`     // JSON or Netscape cookies.txt file
+	StorageState string            `yaml:"storage_state,omitempty" json:"storage_state,omitempty"` // Playwright storageState JSON file
+	LoginScript  string            `yaml:"login_script,omitempty" json:"login_script,omitempty"`   // YAML/JSON login steps run before crawling
 }
 
 // getConfigPath returns the path to the config file
@@ -72,7 +106,12 @@ func SaveConfig(config *Config) error {
 		return err
 	}
 
-	return os.WriteFile(configPath, data, 0644)
+	// The file can hold auth secrets, so only the owner may read it. WriteFile
+	// keeps the mode of an existing file, which older versions created as 0644.
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(configPath, 0600)
 }
 
 // LoadOrCreateConfig loads existing config or creates a new one with defaults
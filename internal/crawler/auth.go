@@ -0,0 +1,274 @@
+package crawler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AuthOptions describes how the crawler authenticates against the site
+type AuthOptions struct {
+	Headers          map[string]string // Extra HTTP headers sent with requests to the audited host
+	BasicAuth        *BasicAuth        // HTTP basic auth credentials
+	Cookies          []Cookie          // Cookies loaded from a cookie file
+	StorageStatePath string            // Playwright storageState JSON (cookies and local storage)
+	LoginScript      *LoginScript      // Form login performed once before crawling
+}
+
+// BasicAuth holds HTTP basic auth credentials
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Cookie is a browser cookie loaded from a cookie file or storage state
+type Cookie struct {
+	Name     string  `json:"name"`
+	Value    string  `json:"value"`
+	Domain   string  `json:"domain"`
+	Path     string  `json:"path"`
+	Expires  float64 `json:"expires"`
+	Secure   bool    `json:"secure"`
+	HTTPOnly bool    `json:"httpOnly"`
+}
+
+// LoginScript is a sequence of browser steps that logs in before the crawl starts
+type LoginScript struct {
+	Steps []LoginStep `yaml:"steps" json:"steps"`
+}
+
+// LoginStep is a single action in a login script. Exactly one action field is set.
+type LoginStep struct {
+	Goto       string `yaml:"goto,omitempty" json:"goto,omitempty"`                 // Navigate to a URL (relative to the base URL)
+	Fill       string `yaml:"fill,omitempty" json:"fill,omitempty"`                 // Selector of an input to fill with Value
+	Value      string `yaml:"value,omitempty" json:"value,omitempty"`               // Value for Fill, supports ${ENV_VAR}
+	Click      string `yaml:"click,omitempty" json:"click,omitempty"`               // Selector of an element to click
+	WaitFor    string `yaml:"wait_for,omitempty" json:"wait_for,omitempty"`         // Selector to wait for
+	WaitForURL string `yaml:"wait_for_url,omitempty" json:"wait_for_url,omitempty"` // URL (glob) to wait for
+}
+
+// IsZero reports whether no authentication is configured
+func (a AuthOptions) IsZero() bool {
+	return len(a.Headers) == 0 && a.BasicAuth == nil && len(a.Cookies) == 0 &&
+		a.StorageStatePath == "" && a.LoginScript == nil
+}
+
+// ParseBasicAuth parses "user:password" credentials
+func ParseBasicAuth(value string) (*BasicAuth, error) {
+	username, password, ok := strings.Cut(value, ":")
+	if !ok || username == "" {
+		return nil, fmt.Errorf("basic auth must be in the form user:password")
+	}
+	return &BasicAuth{Username: username, Password: password}, nil
+}
+
+// ParseHeader parses a "Name: value" header line
+func ParseHeader(value string) (string, string, error) {
+	name, headerValue, ok := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("header %q must be in the form \"Name: value\"", value)
+	}
+	return name, strings.TrimSpace(headerValue), nil
+}
+
+// LoadCookieFile reads cookies from a JSON export (array of cookies or a
+// Playwright storageState file) or a Netscape cookies.txt file
+func LoadCookieFile(path string) ([]Cookie, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cookie file: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return parseJSONCookies(trimmed)
+	}
+
+	return parseNetscapeCookies(data)
+}
+
+// parseJSONCookies accepts either a bare cookie array or a storageState document
+func parseJSONCookies(data []byte) ([]Cookie, error) {
+	if data[0] == '[' {
+		var cookies []Cookie
+		if err := json.Unmarshal(data, &cookies); err != nil {
+			return nil, fmt.Errorf("invalid cookie JSON: %w", err)
+		}
+		return cookies, nil
+	}
+
+	var state struct {
+		Cookies []Cookie `json:"cookies"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid storage state JSON: %w", err)
+	}
+	return state.Cookies, nil
+}
+
+// parseNetscapeCookies parses the tab-separated cookies.txt format used by curl and browser extensions
+func parseNetscapeCookies(data []byte) ([]Cookie, error) {
+	var cookies []Cookie
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// Only trim line endings: a cookie with an empty value ends with a tab
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			httpOnly = true
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("invalid cookies.txt line: %q", line)
+		}
+
+		expires, _ := strconv.ParseFloat(fields[4], 64)
+		cookies = append(cookies, Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Expires:  expires,
+			Name:     fields[5],
+			Value:    fields[6],
+			HTTPOnly: httpOnly,
+		})
+	}
+
+	return cookies, scanner.Err()
+}
+
+// LoadLoginScript reads a login script from a YAML or JSON file.
+// Fill values may reference environment variables, e.g. ${LOGIN_PASSWORD}.
+func LoadLoginScript(path string) (*LoginScript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read login script: %w", err)
+	}
+
+	var script LoginScript
+	if err := yaml.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("invalid login script: %w", err)
+	}
+	if len(script.Steps) == 0 {
+		return nil, fmt.Errorf("login script %s has no steps", path)
+	}
+
+	for i := range script.Steps {
+		script.Steps[i].Value = os.ExpandEnv(script.Steps[i].Value)
+	}
+
+	return &script, nil
+}
+
+// withDefaults fills in the cookie domain and path from the base URL when missing
+func (ck Cookie) withDefaults(baseURL *url.URL) Cookie {
+	if ck.Domain == "" && baseURL != nil {
+		ck.Domain = baseURL.Hostname()
+	}
+	if ck.Path == "" {
+		ck.Path = "/"
+	}
+	return ck
+}
+
+// authTransport adds the configured headers and basic auth to requests for the
+// audited host. Redirects and sitemaps on other hosts get neither.
+type authTransport struct {
+	base http.RoundTripper
+	auth AuthOptions
+	host string // Host (and port) of the base URL
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.EqualFold(req.URL.Host, t.host) {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	for name, value := range t.auth.Headers {
+		req.Header.Set(name, value)
+	}
+	if t.auth.BasicAuth != nil {
+		req.SetBasicAuth(t.auth.BasicAuth.Username, t.auth.BasicAuth.Password)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// origin returns the scheme://host[:port] origin of a URL as browsers compare
+// it, without the default port. It is empty for URLs that cannot be parsed.
+func origin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6
+	}
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	return scheme + "://" + host
+}
+
+// newCookieJar builds a cookie jar holding the configured cookies, including
+// those from a Playwright storageState file
+func newCookieJar(auth AuthOptions, baseURL *url.URL) (http.CookieJar, error) {
+	cookies := auth.Cookies
+	if auth.StorageStatePath != "" {
+		stateCookies, err := LoadCookieFile(auth.StorageStatePath)
+		if err != nil {
+			return nil, err
+		}
+		cookies = append(cookies, stateCookies...)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ck := range cookies {
+		ck = ck.withDefaults(baseURL)
+		host := strings.TrimPrefix(ck.Domain, ".")
+		scheme := "http"
+		if ck.Secure {
+			scheme = "https"
+		}
+
+		cookie := &http.Cookie{
+			Name:     ck.Name,
+			Value:    ck.Value,
+			Path:     ck.Path,
+			Secure:   ck.Secure,
+			HttpOnly: ck.HTTPOnly,
+		}
+		// Leading-dot domains apply to subdomains; host-only cookies omit Domain
+		if strings.HasPrefix(ck.Domain, ".") {
+			cookie.Domain = host
+		}
+
+		jar.SetCookies(&url.URL{Scheme: scheme, Host: host, Path: ck.Path}, []*http.Cookie{cookie})
+	}
+
+	return jar, nil
+}
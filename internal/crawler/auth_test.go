@@ -0,0 +1,206 @@
+package crawler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBasicAuth(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    *BasicAuth
+		wantErr bool
+	}{
+		{value: "admin:secret", want: &BasicAuth{Username: "admin", Password: "secret"}},
+		{value: "admin:", want: &BasicAuth{Username: "admin", Password: ""}},
+		{value: "admin:pa:ss", want: &BasicAuth{Username: "admin", Password: "pa:ss"}}, // Passwords may contain colons
+		{value: "admin", wantErr: true},
+		{value: ":secret", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBasicAuth(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBasicAuth(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseBasicAuth(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantName  string
+		wantValue string
+		wantErr   bool
+	}{
+		{value: "Authorization: Bearer abc", wantName: "Authorization", wantValue: "Bearer abc"},
+		{value: "  X-Token :  abc  ", wantName: "X-Token", wantValue: "abc"},
+		{value: "X-Url: http://localhost:3000", wantName: "X-Url", wantValue: "http://localhost:3000"},
+		{value: "X-Empty:", wantName: "X-Empty", wantValue: ""},
+		{value: "Authorization Bearer abc", wantErr: true},
+		{value: ": abc", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		name, value, err := ParseHeader(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseHeader(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if name != tt.wantName || value != tt.wantValue {
+			t.Errorf("ParseHeader(%q) = %q, %q, want %q, %q", tt.value, name, value, tt.wantName, tt.wantValue)
+		}
+	}
+}
+
+func TestLoadCookieFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Cookie
+		wantErr string
+	}{
+		{
+			name:    "JSON array",
+			content: `[{"name": "session", "value": "abc", "domain": ".example.com", "path": "/", "secure": true, "httpOnly": true}]`,
+			want:    []Cookie{{Name: "session", Value: "abc", Domain: ".example.com", Path: "/", Secure: true, HTTPOnly: true}},
+		},
+		{
+			name:    "storage state",
+			content: `{"cookies": [{"name": "session", "value": "abc", "domain": "localhost", "expires": 1700000000}], "origins": []}`,
+			want:    []Cookie{{Name: "session", Value: "abc", Domain: "localhost", Expires: 1700000000}},
+		},
+		{
+			name: "Netscape",
+			content: "# Netscape HTTP Cookie File\n\n" +
+				".example.com\tTRUE\t/\tTRUE\t1700000000\tsession\tabc\r\n" +
+				"#HttpOnly_localhost\tFALSE\t/admin\tFALSE\t0\ttoken\txyz\n" +
+				"localhost\tFALSE\t/\tFALSE\t0\tempty\t\n",
+			want: []Cookie{
+				{Name: "session", Value: "abc", Domain: ".example.com", Path: "/", Secure: true, Expires: 1700000000},
+				{Name: "token", Value: "xyz", Domain: "localhost", Path: "/admin", HTTPOnly: true},
+				{Name: "empty", Value: "", Domain: "localhost", Path: "/"},
+			},
+		},
+		{name: "invalid JSON", content: `[{"name": `, wantErr: "invalid cookie JSON"},
+		{name: "invalid storage state", content: `{"cookies": {}}`, wantErr: "invalid storage state JSON"},
+		{name: "invalid Netscape line", content: "localhost\tFALSE\t/\n", wantErr: "invalid cookies.txt line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cookies")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadCookieFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadCookieFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadCookieFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadCookieFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("LoadCookieFile() read a missing file")
+	}
+}
+
+func TestAuthTransport(t *testing.T) {
+	type seen struct {
+		token string
+		user  string
+	}
+	requests := make(chan seen, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ := r.BasicAuth()
+		requests <- seen{token: r.Header.Get("X-Token"), user: user}
+	})
+	site := httptest.NewServer(handler)
+	defer site.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	host := strings.TrimPrefix(site.URL, "http://")
+	client := &http.Client{Transport: &authTransport{
+		base: http.DefaultTransport,
+		auth: AuthOptions{
+			Headers:   map[string]string{"X-Token": "secret"},
+			BasicAuth: &BasicAuth{Username: "admin", Password: "pass"},
+		},
+		host: host,
+	}}
+
+	tests := []struct {
+		url  string
+		want seen
+	}{
+		{url: site.URL + "/page", want: seen{token: "secret", user: "admin"}},
+		{url: other.URL + "/page", want: seen{}}, // Credentials stay on the audited host
+	}
+	for _, tt := range tests {
+		resp, err := client.Get(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := <-requests; got != tt.want {
+			t.Errorf("GET %s sent %+v, want %+v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestNewCookieJar(t *testing.T) {
+	base, _ := url.Parse("http://site.test:3000/")
+	jar, err := newCookieJar(AuthOptions{Cookies: []Cookie{
+		{Name: "host", Value: "1"}, // Domain and path default to the base URL
+		{Name: "admin", Value: "2", Domain: "site.test", Path: "/admin"},
+		{Name: "secure", Value: "3", Domain: "site.test", Secure: true},
+		{Name: "wide", Value: "4", Domain: ".example.com"},
+	}}, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"http://site.test:3000/", "host=1"},
+		{"http://site.test:3000/admin/users", "admin=2; host=1"},
+		{"https://site.test/", "host=1; secure=3"},
+		{"http://www.example.com/", "wide=4"},
+		{"http://other.test/", ""},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		var names []string
+		for _, cookie := range jar.Cookies(u) {
+			names = append(names, cookie.Name+"="+cookie.Value)
+		}
+		if got := strings.Join(names, "; "); got != tt.want {
+			t.Errorf("cookies for %s = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,442 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	pwsetup "github.com/ugolbck/seofordev/internal/playwright"
+)
+
+// browserFetcher renders pages in headless Chromium using Playwright
+type browserFetcher struct {
+	opts    FetcherOptions
+	pw      *playwright.Playwright
+	browser playwright.Browser
+	state   *playwright.StorageState // Session after cookies and login, copied into every worker context
+}
+
+// browserSession is a worker's own browser context and page, reused across navigations
+type browserSession struct {
+	fetcher     *browserFetcher
+	device      *Device // Emulated device; nil for the browser's default viewport
+	context     playwright.BrowserContext
+	page        playwright.Page
+	diagnostics diagnosticsCollector // Console errors and failed requests of the current fetch
+}
+
+func (f *browserFetcher) Start() error {
+	// Check if Playwright is installed (should have been installed at startup)
+	if err := pwsetup.CheckPlaywrightInstalled(); err != nil {
+		return fmt.Errorf("Playwright not properly installed: %w", err)
+	}
+
+	// Set up paths for Playwright
+	driverDir := pwsetup.GetPlaywrightDir()
+	browsersDir := filepath.Join(driverDir, "browsers")
+	os.Setenv("PLAYWRIGHT_BROWSERS_PATH", browsersDir)
+
+	// Run Playwright with custom driver directory
+	runOptions := &playwright.RunOptions{
+		DriverDirectory: driverDir,
+	}
+
+	var err error
+	f.pw, err = playwright.Run(runOptions)
+	if err != nil {
+		return fmt.Errorf("could not launch Playwright: %w", err)
+	}
+
+	f.browser, err = f.pw.Chromium.Launch()
+	if err != nil {
+		return fmt.Errorf("could not launch browser: %w", err)
+	}
+
+	return f.prepareSession()
+}
+
+// prepareSession loads cookies and runs the login script once in a setup context,
+// then keeps the resulting storage state for the worker contexts
+func (f *browserFetcher) prepareSession() error {
+	auth := f.opts.Auth
+	if len(auth.Cookies) == 0 && auth.StorageStatePath == "" && auth.LoginScript == nil {
+		return nil
+	}
+
+	setup, err := f.browser.NewContext(f.contextOptions(nil))
+	if err != nil {
+		return fmt.Errorf("could not create browser context: %w", err)
+	}
+	defer setup.Close()
+
+	if err := f.routeRequests(setup, nil); err != nil {
+		return fmt.Errorf("could not set up auth headers: %w", err)
+	}
+
+	if err := f.addCookies(setup); err != nil {
+		return err
+	}
+
+	if auth.LoginScript != nil {
+		if err := f.runLoginScript(setup, auth.LoginScript); err != nil {
+			return fmt.Errorf("login script failed: %w", err)
+		}
+	}
+
+	f.state, err = setup.StorageState()
+	if err != nil {
+		return fmt.Errorf("could not save browser session: %w", err)
+	}
+	return nil
+}
+
+// contextOptions builds the browser context options, including authentication.
+// A device overrides the profile's viewport but keeps its User-Agent.
+func (f *browserFetcher) contextOptions(device *Device) playwright.BrowserNewContextOptions {
+	options := playwright.BrowserNewContextOptions{
+		IgnoreHttpsErrors: playwright.Bool(f.opts.Insecure),
+	}
+
+	profile := f.opts.Profile
+	if profile != nil {
+		options.UserAgent = playwright.String(profile.UserAgent)
+		emulateScreen(&options, profile.Width, profile.Height, profile.Mobile)
+	}
+	if device != nil {
+		if profile == nil {
+			options.UserAgent = playwright.String(device.UserAgent)
+		}
+		emulateScreen(&options, device.Width, device.Height, device.Mobile)
+	}
+
+	// Headers are added per request by routeRequests, so only the audited origin gets them
+	auth := f.opts.Auth
+	if auth.BasicAuth != nil {
+		options.HttpCredentials = &playwright.HttpCredentials{
+			Username: auth.BasicAuth.Username,
+			Password: auth.BasicAuth.Password,
+			Origin:   playwright.String(origin(f.opts.BaseURL)),
+		}
+	}
+	if f.state != nil {
+		options.StorageState = f.state.ToOptionalStorageState()
+	} else if auth.StorageStatePath != "" {
+		options.StorageStatePath = playwright.String(auth.StorageStatePath)
+	}
+
+	return options
+}
+
+// emulateScreen sets the viewport, and for phones touch support and a high-density screen
+func emulateScreen(options *playwright.BrowserNewContextOptions, width, height int, mobile bool) {
+	options.Viewport = &playwright.Size{Width: width, Height: height}
+	options.IsMobile = playwright.Bool(mobile)
+	options.HasTouch = playwright.Bool(mobile)
+	if mobile {
+		options.DeviceScaleFactor = playwright.Float(2.625)
+	} else {
+		options.DeviceScaleFactor = playwright.Float(1)
+	}
+}
+
+// addCookies loads cookies from the cookie file into the setup context
+func (f *browserFetcher) addCookies(context playwright.BrowserContext) error {
+	if len(f.opts.Auth.Cookies) == 0 {
+		return nil
+	}
+
+	baseURL, _ := url.Parse(f.opts.BaseURL)
+	cookies := make([]playwright.OptionalCookie, 0, len(f.opts.Auth.Cookies))
+	for _, ck := range f.opts.Auth.Cookies {
+		ck = ck.withDefaults(baseURL)
+		cookie := playwright.OptionalCookie{
+			Name:     ck.Name,
+			Value:    ck.Value,
+			Domain:   playwright.String(ck.Domain),
+			Path:     playwright.String(ck.Path),
+			Secure:   playwright.Bool(ck.Secure),
+			HttpOnly: playwright.Bool(ck.HTTPOnly),
+		}
+		if ck.Expires > 0 {
+			cookie.Expires = playwright.Float(ck.Expires)
+		}
+		cookies = append(cookies, cookie)
+	}
+
+	if err := context.AddCookies(cookies); err != nil {
+		return fmt.Errorf("could not add cookies: %w", err)
+	}
+	return nil
+}
+
+// runLoginScript performs the scripted login in the setup context, so the
+// resulting session cookies are used by every worker afterwards
+func (f *browserFetcher) runLoginScript(context playwright.BrowserContext, script *LoginScript) error {
+	page, err := context.NewPage()
+	if err != nil {
+		return fmt.Errorf("could not open page: %w", err)
+	}
+	defer page.Close()
+
+	baseURL, err := url.Parse(f.opts.BaseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	for i, step := range script.Steps {
+		var err error
+		switch {
+		case step.Goto != "":
+			target, parseErr := baseURL.Parse(step.Goto)
+			if parseErr != nil {
+				return fmt.Errorf("step %d: invalid URL %q: %w", i+1, step.Goto, parseErr)
+			}
+			_, err = page.Goto(target.String(), playwright.PageGotoOptions{
+				WaitUntil: playwright.WaitUntilStateLoad,
+			})
+		case step.Fill != "":
+			err = page.Fill(step.Fill, step.Value)
+		case step.Click != "":
+			err = page.Click(step.Click)
+		case step.WaitFor != "":
+			_, err = page.WaitForSelector(step.WaitFor)
+		case step.WaitForURL != "":
+			err = page.WaitForURL(step.WaitForURL)
+		default:
+			return fmt.Errorf("step %d: no action (expected goto, fill, click, wait_for or wait_for_url)", i+1)
+		}
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+
+	// Let any post-login navigation settle before crawling starts
+	return page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+		State: playwright.LoadStateNetworkidle,
+	})
+}
+
+func (f *browserFetcher) Close() {
+	if f.browser != nil {
+		f.browser.Close()
+	}
+	if f.pw != nil {
+		f.pw.Stop()
+	}
+}
+
+// NewSession opens a browser context and page for one worker. Requests for the
+// configured resource types are aborted so only what the DOM needs is loaded.
+func (f *browserFetcher) NewSession(device *Device) (Session, error) {
+	context, err := f.browser.NewContext(f.contextOptions(device))
+	if err != nil {
+		return nil, fmt.Errorf("could not create browser context: %w", err)
+	}
+
+	blocked := make(map[string]bool, len(f.opts.BlockResources))
+	for _, resourceType := range f.opts.BlockResources {
+		blocked[resourceType] = true
+	}
+
+	if err := f.routeRequests(context, blocked); err != nil {
+		context.Close()
+		return nil, fmt.Errorf("could not set up request routing: %w", err)
+	}
+
+	session := &browserSession{fetcher: f, device: device, context: context}
+	session.diagnostics.blocked = blocked
+	return session, nil
+}
+
+// routeRequests aborts requests for blocked resource types and adds the configured
+// headers to requests for the audited origin. Third-party subresources (CDNs,
+// analytics, fonts) never see them.
+func (f *browserFetcher) routeRequests(context playwright.BrowserContext, blocked map[string]bool) error {
+	headers := f.opts.Auth.Headers
+	if len(blocked) == 0 && len(headers) == 0 {
+		return nil
+	}
+
+	auditedOrigin := origin(f.opts.BaseURL)
+	return context.Route("**/*", func(route playwright.Route) {
+		request := route.Request()
+		if blocked[request.ResourceType()] {
+			route.Abort()
+			return
+		}
+		if len(headers) == 0 || origin(request.URL()) != auditedOrigin {
+			route.Continue()
+			return
+		}
+
+		merged := make(map[string]string)
+		for name, value := range request.Headers() {
+			merged[name] = value
+		}
+		for name, value := range headers {
+			merged[strings.ToLower(name)] = value
+		}
+		route.Continue(playwright.RouteContinueOptions{Headers: merged})
+	})
+}
+
+func (s *browserSession) Close() {
+	s.context.Close()
+}
+
+// reusablePage returns the session's page, opening a new one if it was never
+// opened or has been closed (e.g. after a crash)
+func (s *browserSession) reusablePage() (playwright.Page, error) {
+	if s.page != nil && !s.page.IsClosed() {
+		return s.page, nil
+	}
+
+	page, err := s.context.NewPage()
+	if err != nil {
+		return nil, fmt.Errorf("could not open page: %w", err)
+	}
+	s.diagnostics.watch(page)
+	s.page = page
+	return page, nil
+}
+
+func (s *browserSession) Fetch(ctx context.Context, pageURL string) (*PageResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	page, err := s.reusablePage()
+	if err != nil {
+		return nil, err
+	}
+
+	s.diagnostics.start()
+	defer s.diagnostics.stop()
+
+	response, err := page.Goto(pageURL, playwright.PageGotoOptions{
+		WaitUntil: playwright.WaitUntilStateLoad,
+		Timeout:   playwright.Float(15000),
+	})
+	if err != nil {
+		// Chromium only reports ERR_TOO_MANY_REDIRECTS, so trace the chain over HTTP
+		if strings.Contains(err.Error(), "ERR_TOO_MANY_REDIRECTS") {
+			if client, clientErr := newHTTPClient(s.fetcher.opts, 15*time.Second); clientErr == nil {
+				if resp, hops, loop, _ := followRedirects(ctx, client, pageURL, nil); loop {
+					return redirectLoopResult(pageURL, hops), nil
+				} else if resp != nil {
+					resp.Body.Close()
+				}
+			}
+		}
+		return nil, err
+	}
+
+	// Get status code and redirect chain from response
+	statusCode := 0
+	finalURL := pageURL
+	var redirects []RedirectHop
+	var headers map[string]string
+	if response != nil {
+		statusCode = response.Status()
+		finalURL = response.URL()
+		redirects = browserRedirectChain(response.Request())
+
+		// AllHeaders includes headers Chromium hides from Headers(), e.g. Set-Cookie
+		if headers, err = response.AllHeaders(); err != nil {
+			headers = response.Headers()
+		}
+	}
+
+	// Get page content
+	content, err := page.Content()
+	if err != nil {
+		content = "" // Empty content but still record the page with its status code
+	}
+
+	result := &PageResult{
+		URL:         pageURL,
+		Content:     content,
+		StatusCode:  statusCode,
+		FinalURL:    finalURL,
+		Redirects:   redirects,
+		Headers:     headers,
+		Diagnostics: s.diagnostics.stop(),
+		Performance: measurePerformance(page),
+	}
+	if s.device != nil {
+		result.Device = s.device.Name
+		result.Viewport = measureViewport(page)
+	}
+
+	return result, nil
+}
+
+// Screenshot saves a PNG of the page loaded by the last Fetch
+func (s *browserSession) Screenshot(path string, fullPage bool) error {
+	if s.page == nil || s.page.IsClosed() {
+		return fmt.Errorf("no page to capture")
+	}
+
+	_, err := s.page.Screenshot(playwright.PageScreenshotOptions{
+		Path:     playwright.String(path),
+		FullPage: playwright.Bool(fullPage),
+		Type:     playwright.ScreenshotTypePng,
+		Timeout:  playwright.Float(15000),
+	})
+	if err != nil {
+		return fmt.Errorf("could not capture screenshot: %w", err)
+	}
+	return nil
+}
+
+// measureViewport compares the rendered document width with the viewport width
+func measureViewport(page playwright.Page) *ViewportMetrics {
+	value, err := page.Evaluate(`() => [window.innerWidth, document.documentElement.scrollWidth]`)
+	if err != nil {
+		return nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok || len(values) != 2 {
+		return nil
+	}
+	width, widthOK := jsInt(values[0])
+	scrollWidth, scrollOK := jsInt(values[1])
+	if !widthOK || !scrollOK {
+		return nil
+	}
+
+	return &ViewportMetrics{Width: width, ScrollWidth: scrollWidth}
+}
+
+// jsInt converts a number returned by page.Evaluate
+func jsInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// browserRedirectChain walks back from the final navigation request to the first one
+func browserRedirectChain(request playwright.Request) []RedirectHop {
+	var hops []RedirectHop
+	for r := request.RedirectedFrom(); r != nil; r = r.RedirectedFrom() {
+		hop := RedirectHop{URL: r.URL()}
+		if resp, err := r.Response(); err == nil && resp != nil {
+			hop.StatusCode = resp.Status()
+			hop.Location = resp.Headers()["location"]
+		}
+		hops = append([]RedirectHop{hop}, hops...)
+	}
+	return hops
+}
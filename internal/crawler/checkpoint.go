@@ -0,0 +1,154 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// checkpointInterval is how often the frontier is saved while crawling
+const checkpointInterval = 5 * time.Second
+
+// Checkpoint is a snapshot of crawl progress used to resume an interrupted crawl
+type Checkpoint struct {
+	BaseURL     string           `json:"base_url"`
+	SavedAt     time.Time        `json:"saved_at"`
+	Visited     []string         `json:"visited"`
+	Pending     []CheckpointPage `json:"pending"` // Queued or being fetched when the checkpoint was saved
+	Crawled     []CheckpointPage `json:"crawled"` // Pages fetched so far
+	SitemapURLs []string         `json:"sitemap_urls,omitempty"`
+	LinkedURLs  []string         `json:"linked_urls,omitempty"`
+	Skipped     []SkippedURL     `json:"skipped,omitempty"`
+}
+
+// CheckpointPage is a URL in the frontier or one that was already fetched
+type CheckpointPage struct {
+	URL        string `json:"url"`
+	Depth      int    `json:"depth"`
+	Source     string `json:"source"`
+	LinkKind   string `json:"link_kind,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+}
+
+// LoadCheckpoint reads a checkpoint written by a previous crawl
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+
+	return &checkpoint, nil
+}
+
+// Resume restores the frontier and visited set from a checkpoint. Crawled pages
+// missing from done are fetched again, as their results were lost with the
+// interrupted run. Call Resume before Start.
+func (c *Crawler) Resume(checkpoint *Checkpoint, done map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	refetch := make(map[string]bool)
+	for _, page := range checkpoint.Crawled {
+		if done[page.URL] {
+			c.crawled[page.URL] = page
+			c.pageCount++
+			continue
+		}
+		refetch[page.URL] = true
+		c.resumeTasks = append(c.resumeTasks, crawlTask{URL: page.URL, Depth: page.Depth, Source: page.Source, LinkKind: page.LinkKind})
+	}
+
+	for _, page := range checkpoint.Pending {
+		refetch[page.URL] = true
+		c.resumeTasks = append(c.resumeTasks, crawlTask{URL: page.URL, Depth: page.Depth, Source: page.Source, LinkKind: page.LinkKind})
+	}
+
+	for _, u := range checkpoint.Visited {
+		if !refetch[u] {
+			c.visited[u] = true
+		}
+	}
+	for _, u := range checkpoint.SitemapURLs {
+		c.sitemapURLs[u] = true
+	}
+	for _, u := range checkpoint.LinkedURLs {
+		c.linkedURLs[u] = true
+	}
+	for _, skip := range checkpoint.Skipped {
+		c.skipped[skip.URL] = skip.Reason
+	}
+}
+
+// checkpoint takes a snapshot of the current crawl state
+func (c *Crawler) checkpoint() *Checkpoint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	checkpoint := &Checkpoint{
+		BaseURL:     c.BaseURL,
+		SavedAt:     time.Now(),
+		Visited:     sortedKeys(c.visited),
+		SitemapURLs: sortedKeys(c.sitemapURLs),
+		LinkedURLs:  sortedKeys(c.linkedURLs),
+	}
+
+	for _, task := range c.frontier.snapshot() {
+		checkpoint.Pending = append(checkpoint.Pending, CheckpointPage{URL: task.URL, Depth: task.Depth, Source: task.Source, LinkKind: task.LinkKind})
+	}
+	for _, page := range c.crawled {
+		checkpoint.Crawled = append(checkpoint.Crawled, page)
+	}
+	for u, reason := range c.skipped {
+		checkpoint.Skipped = append(checkpoint.Skipped, SkippedURL{URL: u, Reason: reason})
+	}
+
+	sort.Slice(checkpoint.Pending, func(i, j int) bool { return checkpoint.Pending[i].URL < checkpoint.Pending[j].URL })
+	sort.Slice(checkpoint.Crawled, func(i, j int) bool { return checkpoint.Crawled[i].URL < checkpoint.Crawled[j].URL })
+	sort.Slice(checkpoint.Skipped, func(i, j int) bool { return checkpoint.Skipped[i].URL < checkpoint.Skipped[j].URL })
+
+	return checkpoint
+}
+
+// saveCheckpoint writes the crawl state to CheckpointPath, if set
+func (c *Crawler) saveCheckpoint() error {
+	if c.CheckpointPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c.checkpoint())
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.CheckpointPath), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	// Write to a temporary file first so an interruption never leaves a truncated checkpoint
+	tmpPath := c.CheckpointPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.CheckpointPath); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,237 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"math/bits"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// requestLog records the pages a test server was asked for
+type requestLog struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (l *requestLog) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" && !strings.HasPrefix(r.URL.Path, "/sitemap") {
+			l.mu.Lock()
+			l.paths = append(l.paths, r.URL.Path)
+			l.mu.Unlock()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *requestLog) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	paths := l.paths
+	l.paths = nil
+	return paths
+}
+
+// pageDepth is the BFS depth of a page of newBenchmarkSite
+func pageDepth(path string) int {
+	index, _ := strconv.Atoi(strings.TrimPrefix(path, "/page/"))
+	return bits.Len(uint(index+1)) - 1
+}
+
+func TestCheckpointResume(t *testing.T) {
+	const pages = 31 // Five full levels of the binary tree
+	site := newBenchmarkSite(pages)
+	defer site.Close()
+	requests := &requestLog{}
+	site.Config.Handler = requests.wrap(site.Config.Handler)
+	checkpointPath := filepath.Join(t.TempDir(), "frontier.json")
+
+	// First run: interrupt the crawl after a few pages
+	ctx, cancel := context.WithCancel(context.Background())
+	first := NewCrawler(ctx, site.URL, 1, 0, 0, nil)
+	first.Renderer = RendererHTTP
+	first.CheckpointPath = checkpointPath
+
+	done := make(map[string]bool)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for page := range first.Pages() {
+			done[page.URL] = true
+			if len(done) == 5 {
+				cancel()
+			}
+		}
+	}()
+	if err := first.Start(); err != nil {
+		t.Fatal(err)
+	}
+	<-finished
+	firstRequests := requests.take()
+	if len(done) >= pages {
+		t.Fatalf("first run crawled all %d pages; it was not interrupted", len(done))
+	}
+
+	// Second run: resume from the saved checkpoint
+	checkpoint, err := LoadCheckpoint(checkpointPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoint.Pending) == 0 {
+		t.Fatal("checkpoint has no pending pages")
+	}
+
+	second := NewCrawler(context.Background(), site.URL, 1, 0, 0, nil)
+	second.Renderer = RendererHTTP
+	second.Resume(checkpoint, done)
+
+	var resumed []PageResult
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for page := range second.Pages() {
+			resumed = append(resumed, page)
+		}
+	}()
+	if err := second.Start(); err != nil {
+		t.Fatal(err)
+	}
+	<-collected
+
+	// Every page is crawled exactly once across both runs
+	seen := make(map[string]bool)
+	for u := range done {
+		seen[u] = true
+	}
+	for _, page := range resumed {
+		if seen[page.URL] {
+			t.Errorf("%s was crawled again after resuming", page.URL)
+		}
+		seen[page.URL] = true
+	}
+	if len(seen) != pages {
+		t.Errorf("%d distinct pages crawled, want %d", len(seen), pages)
+	}
+	for _, path := range requests.take() {
+		if done[site.URL+path] || (path == "/" && done[site.URL+"/"]) {
+			t.Errorf("%s was requested again after resuming (first run requested %v)", path, firstRequests)
+		}
+	}
+
+	// The resumed crawl keeps BFS depths and order
+	lastDepth := 0
+	for _, page := range resumed {
+		path := strings.TrimPrefix(page.URL, site.URL)
+		if want := pageDepth(path); page.Depth != want {
+			t.Errorf("%s depth = %d, want %d", path, page.Depth, want)
+		}
+		if page.Depth < lastDepth {
+			t.Errorf("%s at depth %d crawled after depth %d", path, page.Depth, lastDepth)
+		}
+		lastDepth = page.Depth
+	}
+
+	if stats := second.GetCrawlStats(); stats.PagesCrawled != pages {
+		t.Errorf("PagesCrawled = %d, want %d including the pages from the first run", stats.PagesCrawled, pages)
+	}
+}
+
+func TestResume(t *testing.T) {
+	checkpoint := &Checkpoint{
+		BaseURL: "http://localhost:3000",
+		Visited: []string{
+			"http://localhost:3000/", "http://localhost:3000/a", "http://localhost:3000/b",
+			"http://localhost:3000/lost", "http://localhost:3000/private",
+		},
+		Crawled: []CheckpointPage{
+			{URL: "http://localhost:3000/", Depth: 0, Source: SourceSeed, StatusCode: 200},
+			{URL: "http://localhost:3000/a", Depth: 1, Source: SourceLink, StatusCode: 200},
+			{URL: "http://localhost:3000/lost", Depth: 1, Source: SourceLink, StatusCode: 200}, // Never analyzed
+		},
+		Pending: []CheckpointPage{
+			{URL: "http://localhost:3000/b", Depth: 1, Source: SourceLink},
+			{URL: "http://localhost:3000/a/1", Depth: 2, Source: SourceLink, LinkKind: "nav"},
+		},
+		Skipped: []SkippedURL{{URL: "http://localhost:3000/private", Reason: SkipRobots}},
+	}
+	done := map[string]bool{"http://localhost:3000/": true, "http://localhost:3000/a": true}
+
+	c := NewCrawler(context.Background(), "http://localhost:3000", 1, 0, 0, nil)
+	c.Resume(checkpoint, done)
+
+	for u, want := range map[string]bool{
+		"http://localhost:3000/":        true,
+		"http://localhost:3000/a":       true,
+		"http://localhost:3000/private": true,
+		"http://localhost:3000/lost":    false, // Fetched again, its result was lost
+		"http://localhost:3000/b":       false,
+		"http://localhost:3000/a/1":     false,
+	} {
+		if c.visited[u] != want {
+			t.Errorf("visited[%s] = %v, want %v", u, c.visited[u], want)
+		}
+	}
+	if c.pageCount != 2 {
+		t.Errorf("pageCount = %d, want the 2 analyzed pages", c.pageCount)
+	}
+
+	var tasks []string
+	for _, task := range c.resumeTasks {
+		tasks = append(tasks, task.URL+" "+strconv.Itoa(task.Depth)+" "+task.LinkKind)
+	}
+	want := []string{"http://localhost:3000/lost 1 ", "http://localhost:3000/b 1 ", "http://localhost:3000/a/1 2 nav"}
+	if strings.Join(tasks, ", ") != strings.Join(want, ", ") {
+		t.Errorf("resumeTasks = %v, want %v", tasks, want)
+	}
+	if c.skipped["http://localhost:3000/private"] != SkipRobots {
+		t.Errorf("skipped = %v, want the robots skip kept", c.skipped)
+	}
+}
+
+func TestLoadCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := LoadCheckpoint(filepath.Join(dir, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing checkpoint error = %v, want fs.ErrNotExist", err)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte(`{"base_url": "http://localhost:3000", "pending": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCheckpoint(corrupt); err == nil || !strings.Contains(err.Error(), "invalid checkpoint") {
+		t.Errorf("corrupt checkpoint error = %v, want an invalid checkpoint error", err)
+	}
+
+	// A checkpoint written by saveCheckpoint loads back unchanged
+	c := NewCrawler(context.Background(), "http://localhost:3000", 1, 0, 0, nil)
+	c.CheckpointPath = filepath.Join(dir, "audit", "frontier.json")
+	c.visited["http://localhost:3000/"] = true
+	c.crawled["http://localhost:3000/"] = CheckpointPage{URL: "http://localhost:3000/", Source: SourceSeed, StatusCode: 200}
+	c.frontier.push(crawlTask{URL: "http://localhost:3000/b", Depth: 1, Source: SourceLink})
+	c.frontier.push(crawlTask{URL: "http://localhost:3000/a", Depth: 1, Source: SourceSitemap})
+	if err := c.saveCheckpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(c.CheckpointPath + ".tmp"); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("temporary checkpoint file was left behind")
+	}
+
+	checkpoint, err := LoadCheckpoint(c.CheckpointPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoint.Pending) != 2 || checkpoint.Pending[0].URL != "http://localhost:3000/a" || checkpoint.Pending[0].Source != SourceSitemap {
+		t.Errorf("Pending = %+v, want /a and /b sorted with their sources", checkpoint.Pending)
+	}
+	if len(checkpoint.Crawled) != 1 || checkpoint.Crawled[0].StatusCode != 200 {
+		t.Errorf("Crawled = %+v, want the seed", checkpoint.Crawled)
+	}
+}
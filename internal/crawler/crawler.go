@@ -1,83 +1,126 @@
 package crawler
 
 import (
-	"bufio"
 	"context"
-	"fmt"
 	"io/ioutil"
 	"log"
-	"net/http"
 	"net/url"
-	"os"
-	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/playwright-community/playwright-go"
-	pwsetup "github.com/ugolbck/seofordev/internal/playwright"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ugolbck/seofordev/internal/events"
+	"github.com/ugolbck/seofordev/internal/robots"
+	"github.com/ugolbck/seofordev/internal/urlnorm"
 )
 
 type PageResult struct {
-	URL        string
-	Content    string
-	Depth      int
-	StatusCode int
+	URL          string
+	Content      string
+	Depth        int
+	StatusCode   int
+	Source       string              // How the page was discovered (seed, link or sitemap)
+	LinkKind     string              // Kind of link the page was found in (a, canonical, hreflang, ...) when Source is link
+	FinalURL     string              // URL the page ended up at after redirects
+	Redirects    []RedirectHop       // Redirect hops followed before reaching FinalURL
+	RedirectLoop bool                // Redirects never reached a final page
+	Headers      map[string]string   // Final response headers, keyed by lowercase name
+	Device       string              // Device the page was rendered on; empty without emulation
+	Viewport     *ViewportMetrics    // Rendered width; nil unless the browser renderer measured it
+	Renders      []DeviceRender      // The same page rendered on the other devices
+	Diagnostics  *PageDiagnostics    // JavaScript errors and failed requests; nil without a browser
+	Performance  *PerformanceMetrics // Loading metrics; nil without a browser
+	Screenshots  *PageScreenshots    // Captures of the primary render; nil unless ScreenshotDir is set
+	Raw          *RawResponse        // The page before JavaScript ran; nil unless CompareRaw is set
 }
 
 type Crawler struct {
-	BaseURL        string
-	Concurrency    int
-	MaxPages       int
-	MaxDepth       int
-	IgnorePatterns []string
+	BaseURL         string
+	Concurrency     int
+	MaxPages        int
+	MaxDepth        int
+	IgnorePatterns  []string // Exclude patterns: /regex/, globs such as /blog/** or substrings
+	IncludePatterns []string // When set, only matching URLs are crawled (the start page always is)
+	URLs            []string // List mode: fetch exactly these URLs, without following links or reading sitemaps
+	Renderer        string   // RendererBrowser or RendererHTTP
+	Insecure        bool     // Skip TLS certificate verification
+	Auth            AuthOptions
+	BlockResources  []string       // Browser resource types not loaded, e.g. image, font, media
+	Rate            float64        // Maximum requests per second per host; 0 means unlimited
+	RobotsAgent     string         // Product token robots.txt groups are matched on, e.g. Googlebot
+	Profile         *Profile       // Client to impersonate; nil keeps the renderer's defaults
+	Devices         []Device       // Devices each page is rendered on; links are followed from the first
+	CheckpointPath  string         // Where to periodically save the frontier; empty disables checkpoints
+	ScreenshotDir   string         // Where to save page screenshots; empty disables them (browser renderer only)
+	CompareRaw      bool           // Also fetch each page's raw HTML over HTTP (browser renderer only)
+	RobotsFailOpen  bool           // Crawl without restrictions when robots.txt is unreachable or returns 5xx
+	URLPolicy       urlnorm.Policy // How URLs are canonicalized, e.g. tracking parameters removed
+	OnEvent         events.Handler // Optional; receives discovery and fetch progress
 
 	// State tracking
-	visited   map[string]bool
-	results   []PageResult
-	pageCount int
-
-	// Queue management
-	queue     chan crawlTask
-	queueOpen bool
-	
-	// Worker tracking
-	activeWorkers int
-	workerMu      sync.Mutex
-
-	// Robots.txt handling
-	robotsRules []robotsRule
-	robotsMu    sync.RWMutex
+	visited      map[string]bool
+	pages        chan PageResult // Fetched pages, streamed to the caller
+	emitted      int
+	pageCount    int
+	crawled      map[string]CheckpointPage // Fetched pages with their status code
+	resumeTasks  []crawlTask               // Frontier restored from a checkpoint
+	limitReached bool                      // MaxPages stopped the crawl
+	overLimit    int                       // Tasks handed out after MaxPages was reached
+	crawlTime    time.Duration             // Time spent fetching pages in this run
+	throttled    int                       // Responses that were 429/503 and retried
+
+	// Compiled IncludePatterns and IgnorePatterns, and why URLs were not crawled
+	include []Pattern
+	exclude []Pattern
+	skipped map[string]string
+
+	// Raw HTML session shared by the workers when CompareRaw is set
+	raw Session
+
+	// Fetcher used instead of the Renderer's; lets benchmarks compare fetch strategies
+	fetcher Fetcher
+
+	// Per-host politeness (Rate and robots.txt Crawl-delay)
+	limiter    *hostLimiter
+	crawlDelay time.Duration
+
+	// URLs waiting to be crawled, in breadth-first order
+	frontier *frontier
+
+	// Robots.txt handling; nil until fetched, which allows everything
+	robots   *robots.Robots
+	robotsMu sync.RWMutex
+
+	// Sitemap coverage tracking
+	sitemapURLs map[string]bool
+	linkedURLs  map[string]bool
 
 	// Synchronization
-	mu      sync.RWMutex
-	queueMu sync.Mutex
-	wg      sync.WaitGroup
-	ctx     context.Context
-	cancel  context.CancelFunc
-
-	// Playwright resources
-	pw      *playwright.Playwright
-	browser playwright.Browser
+	mu     sync.RWMutex
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
-type robotsRule struct {
-	userAgent string
-	disallow  []string
-	allow     []string
-}
+// DefaultRobotsAgent is the robots.txt group the crawler obeys unless RobotsAgent is set
+const DefaultRobotsAgent = "Googlebot"
 
 type crawlTask struct {
-	URL   string
-	Depth int
+	URL      string
+	Depth    int
+	Source   string
+	LinkKind string // Kind of link the URL was found in, when Source is SourceLink
 }
 
-func NewCrawler(baseURL string, concurrency, maxPages, maxDepth int, ignorePatterns []string) *Crawler {
+// NewCrawler creates a crawler for baseURL. Cancelling ctx stops the crawl: no new
+// pages are fetched, pages already fetched are still sent on Pages, and Start
+// returns once in-flight requests have ended.
+func NewCrawler(ctx context.Context, baseURL string, concurrency, maxPages, maxDepth int, ignorePatterns []string) *Crawler {
 	// Disable all logging output
 	log.SetOutput(ioutil.Discard)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 
 	return &Crawler{
 		BaseURL:        baseURL,
@@ -85,107 +128,158 @@ func NewCrawler(baseURL string, concurrency, maxPages, maxDepth int, ignorePatte
 		MaxPages:       maxPages,
 		MaxDepth:       maxDepth,
 		IgnorePatterns: ignorePatterns,
+		Renderer:       RendererBrowser,
+		RobotsAgent:    DefaultRobotsAgent,
 		visited:        make(map[string]bool),
-		results:        []PageResult{},
-		queue:          make(chan crawlTask, 1000), // Larger queue to prevent drops
-		queueOpen:      true,
+		crawled:        make(map[string]CheckpointPage),
+		skipped:        make(map[string]string),
+		frontier:       newFrontier(),
+		sitemapURLs:    make(map[string]bool),
+		linkedURLs:     make(map[string]bool),
+		pages:          make(chan PageResult, concurrency),
 		ctx:            ctx,
 		cancel:         cancel,
 	}
 }
 
+// Start crawls the site and blocks until the crawl is finished. Fetched pages are
+// sent on Pages, which must be read concurrently; it is closed when Start returns.
 func (c *Crawler) Start() error {
-	// Initialize Playwright
-	if err := c.initPlaywright(); err != nil {
+	defer close(c.pages)
+
+	if err := c.compileFilters(); err != nil {
+		return err
+	}
+
+	// Initialize the page fetcher (headless browser or plain HTTP)
+	fetcher := c.fetcher
+	if fetcher == nil {
+		var err error
+		if fetcher, err = NewFetcher(c.Renderer, c.fetcherOptions()); err != nil {
+			return err
+		}
+	}
+	if err := fetcher.Start(); err != nil {
 		return err
 	}
-	defer c.cleanup()
+	defer fetcher.Close()
 
 	// Fetch and parse robots.txt
 	c.fetchRobotsTxt()
 
-	// Normalize and add base URL to queue
-	normalizedBase := c.normalizeURL(c.BaseURL)
-	c.addToQueue(crawlTask{URL: normalizedBase, Depth: 0})
+	// Space out requests per host, honoring robots.txt Crawl-delay
+	c.crawlDelay = c.robotsCrawlDelay()
+	c.limiter = newHostLimiter(requestInterval(c.Rate, c.crawlDelay))
 
-	// Start workers
-	for i := 0; i < c.Concurrency; i++ {
-		c.wg.Add(1)
-		go c.worker()
-	}
-
-	// Monitor for completion
-	go c.monitor()
+	if c.listMode() {
+		// Fetch exactly the listed URLs
+		for _, u := range c.URLs {
+			c.addToQueue(crawlTask{URL: c.normalizeURL(u), Depth: 0, Source: SourceList})
+		}
+	} else {
+		// Collect URLs listed in sitemaps (robots.txt Sitemap lines and /sitemap.xml)
+		sitemapURLs := c.fetchSitemaps()
 
-	// Wait for all workers to complete
-	c.wg.Wait()
+		// Normalize and add base URL to queue
+		normalizedBase := c.normalizeURL(c.BaseURL)
+		c.addToQueue(crawlTask{URL: normalizedBase, Depth: 0, Source: SourceSeed})
 
-	return nil
-}
+		// Seed the queue with sitemap URLs so unlinked pages are audited too
+		for _, u := range sitemapURLs {
+			c.addToQueue(crawlTask{URL: u, Depth: 0, Source: SourceSitemap})
+		}
+	}
 
-func (c *Crawler) initPlaywright() error {
-	// Check if Playwright is installed (should have been installed at startup)
-	if err := pwsetup.CheckPlaywrightInstalled(); err != nil {
-		return fmt.Errorf("Playwright not properly installed: %w", err)
+	// Continue the frontier of an interrupted crawl
+	for _, task := range c.resumeTasks {
+		c.addToQueue(task)
 	}
 
-	// Set up paths for Playwright
-	driverDir := pwsetup.GetPlaywrightDir()
-	browsersDir := filepath.Join(driverDir, "browsers")
-	os.Setenv("PLAYWRIGHT_BROWSERS_PATH", browsersDir)
+	// Give each worker its own sessions (browser context and page), one per device, to reuse
+	devices := make([]*Device, 0, len(c.Devices))
+	for i := range c.Devices {
+		devices = append(devices, &c.Devices[i])
+	}
+	if len(devices) == 0 {
+		devices = append(devices, nil)
+	}
 
-	// Run Playwright with custom driver directory
-	runOptions := &playwright.RunOptions{
-		DriverDirectory: driverDir,
+	// Raw HTML comes from a plain HTTP client sending the primary device's User-Agent
+	if _, isHTTP := fetcher.(*httpFetcher); c.CompareRaw && !isHTTP {
+		rawFetcher := &httpFetcher{opts: c.fetcherOptions()}
+		if err := rawFetcher.Start(); err != nil {
+			return err
+		}
+		defer rawFetcher.Close()
+		raw, err := rawFetcher.NewSession(devices[0])
+		if err != nil {
+			return err
+		}
+		c.raw = raw
 	}
 
-	var err error
-	c.pw, err = playwright.Run(runOptions)
-	if err != nil {
-		return fmt.Errorf("could not launch Playwright: %w", err)
+	workers := make([][]Session, 0, c.Concurrency)
+	for i := 0; i < c.Concurrency; i++ {
+		sessions := make([]Session, 0, len(devices))
+		for _, device := range devices {
+			session, err := fetcher.NewSession(device)
+			if err != nil {
+				closeSessions(sessions)
+				for _, w := range workers {
+					closeSessions(w)
+				}
+				return err
+			}
+			sessions = append(sessions, session)
+		}
+		workers = append(workers, sessions)
 	}
 
-	c.browser, err = c.pw.Chromium.Launch()
-	if err != nil {
-		return fmt.Errorf("could not launch browser: %w", err)
+	// Start workers
+	started := time.Now()
+	for _, sessions := range workers {
+		c.wg.Add(1)
+		go c.worker(sessions)
 	}
 
-	return nil
-}
+	// Save checkpoints while crawling
+	go c.monitor()
 
-func (c *Crawler) cleanup() {
-	if c.browser != nil {
-		c.browser.Close()
-	}
-	if c.pw != nil {
-		c.pw.Stop()
+	// Workers exit once the frontier is exhausted or closed
+	c.wg.Wait()
+	c.stop()
+
+	c.mu.Lock()
+	c.crawlTime = time.Since(started)
+	c.mu.Unlock()
+
+	if err := c.saveCheckpoint(); err != nil {
+		return err
 	}
+
+	return nil
 }
 
-func (c *Crawler) worker() {
+// worker crawls pages with its sessions: the first renders the page that is
+// analyzed, the others render it again on the remaining devices
+func (c *Crawler) worker(sessions []Session) {
 	defer c.wg.Done()
+	defer closeSessions(sessions)
 
 	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		case task, ok := <-c.queue:
-			if !ok {
-				return // Queue closed
-			}
-			
-			// Mark worker as active
-			c.workerMu.Lock()
-			c.activeWorkers++
-			c.workerMu.Unlock()
-			
-			c.crawlPage(task.URL, task.Depth)
-			
-			// Mark worker as inactive
-			c.workerMu.Lock()
-			c.activeWorkers--
-			c.workerMu.Unlock()
+		task, ok := c.frontier.next()
+		if !ok {
+			return // Frontier exhausted or crawl stopped
 		}
+
+		c.crawlPage(sessions, task)
+		c.frontier.done(task)
+	}
+}
+
+func closeSessions(sessions []Session) {
+	for _, session := range sessions {
+		session.Close()
 	}
 }
 
@@ -193,74 +287,42 @@ func (c *Crawler) monitor() {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
+	lastCheckpoint := time.Now()
+
 	for {
 		select {
 		case <-c.ctx.Done():
+			// Wake up workers waiting for the next URL
+			c.frontier.close()
 			return
 		case <-ticker.C:
-			c.mu.RLock()
-			pageCount := c.pageCount
-			c.mu.RUnlock()
-
-			c.queueMu.Lock()
-			queueLen := len(c.queue)
-			queueOpen := c.queueOpen
-			c.queueMu.Unlock()
-			
-			c.workerMu.Lock()
-			activeWorkers := c.activeWorkers
-			c.workerMu.Unlock()
-
-			// Check if we've reached max pages
-			if c.MaxPages > 0 && pageCount >= c.MaxPages {
-				c.stop()
-				return
-			}
-
-			// Check if queue is empty AND no workers are actively processing
-			// This prevents race conditions where workers are still discovering links
-			if queueLen == 0 && activeWorkers == 0 && queueOpen {
-				c.stop()
-				return
+			// Periodically save the frontier so an interrupted crawl can resume
+			if time.Since(lastCheckpoint) >= checkpointInterval {
+				c.saveCheckpoint()
+				lastCheckpoint = time.Now()
 			}
 		}
 	}
 }
 
 func (c *Crawler) stop() {
-	c.queueMu.Lock()
-	if c.queueOpen {
-		c.queueOpen = false
-		close(c.queue)
-	}
-	c.queueMu.Unlock()
-
+	c.frontier.close()
 	c.cancel()
 }
 
+// addToQueue adds a task to the frontier; it returns false if the URL was already queued
 func (c *Crawler) addToQueue(task crawlTask) bool {
-	c.queueMu.Lock()
-	defer c.queueMu.Unlock()
-
-	if !c.queueOpen {
+	if !c.frontier.push(task) {
 		return false
 	}
 
-	// Try to add to queue with a timeout to avoid blocking indefinitely
-	select {
-	case c.queue <- task:
-		return true
-	case <-c.ctx.Done():
-		return false
-	case <-time.After(100 * time.Millisecond):
-		// If queue is still full after timeout, skip this link
-		// This prevents hanging while still giving some time for queue to drain
-		return false
-	}
+	c.OnEvent.Publish(events.Event{Type: events.PageDiscovered, URL: task.URL, Depth: task.Depth})
+	return true
 }
 
-func (c *Crawler) crawlPage(pageURL string, depth int) {
-	normalizedURL := c.normalizeURL(pageURL)
+func (c *Crawler) crawlPage(sessions []Session, task crawlTask) {
+	normalizedURL := c.normalizeURL(task.URL)
+	depth := task.Depth
 
 	c.mu.Lock()
 	// Check if already visited
@@ -271,20 +333,16 @@ func (c *Crawler) crawlPage(pageURL string, depth int) {
 
 	// Check if we've reached max pages
 	if c.MaxPages > 0 && c.pageCount >= c.MaxPages {
+		c.limitReached = true
+		c.overLimit++
 		c.mu.Unlock()
 		return
 	}
 
-	// Check if URL should be ignored
-	if c.shouldIgnore(normalizedURL) {
-		c.visited[normalizedURL] = true
-		c.mu.Unlock()
-		return
-	}
-
-	// Check robots.txt rules
-	if c.isDisallowedByRobots(normalizedURL) {
+	// Check include/exclude patterns and robots.txt rules
+	if reason := c.skipReason(normalizedURL, task.Source == SourceSeed || task.Source == SourceList); reason != "" {
 		c.visited[normalizedURL] = true
+		c.recordSkip(normalizedURL, reason)
 		c.mu.Unlock()
 		return
 	}
@@ -292,67 +350,116 @@ func (c *Crawler) crawlPage(pageURL string, depth int) {
 	// Mark as visited and increment page count
 	c.visited[normalizedURL] = true
 	c.pageCount++
+	if c.MaxPages > 0 && c.pageCount >= c.MaxPages {
+		// Stop handing out URLs; the rest of the frontier is reported as not crawled
+		c.limitReached = true
+		c.frontier.close()
+	}
 	c.mu.Unlock()
 
-	// Create page with timeout
-	page, err := c.browser.NewPage()
-	if err != nil {
+	// Fetch page, respecting rate limits and server throttling
+	result, err := c.fetchPolitely(sessions[0], normalizedURL)
+	if err != nil && c.ctx.Err() != nil {
+		// Interrupted rather than failed: put the URL back so a resumed crawl fetches it
+		c.mu.Lock()
+		delete(c.visited, normalizedURL)
+		c.pageCount--
+		c.mu.Unlock()
+		c.frontier.close()
+		c.frontier.requeue(task)
 		return
 	}
-	defer page.Close()
-
-	// Navigate to page
-	ctx, cancel := context.WithTimeout(c.ctx, 15*time.Second)
-	defer cancel()
-
-	response, err := page.Goto(normalizedURL, playwright.PageGotoOptions{
-		WaitUntil: playwright.WaitUntilStateLoad,
-		Timeout:   playwright.Float(15000),
-	})
-
-	// Default status code for errors
-	statusCode := 0
 	if err != nil {
-		// Store failed page
+		// Report failed page
 		c.mu.Lock()
-		c.results = append(c.results, PageResult{
+		c.crawled[normalizedURL] = CheckpointPage{URL: normalizedURL, Depth: depth, Source: task.Source, LinkKind: task.LinkKind}
+		c.mu.Unlock()
+		c.OnEvent.Publish(events.Event{Type: events.PageFetched, URL: normalizedURL, Depth: depth, Error: err.Error()})
+		c.emit(PageResult{
 			URL:        normalizedURL,
 			Content:    "",
 			Depth:      depth,
-			StatusCode: statusCode,
+			StatusCode: 0,
+			Source:     task.Source,
+			LinkKind:   task.LinkKind,
 		})
-		c.mu.Unlock()
 		return
 	}
 
-	// Get status code from response
-	if response != nil {
-		statusCode = response.Status()
+	result.URL = normalizedURL
+	result.Depth = depth
+	result.Source = task.Source
+	result.LinkKind = task.LinkKind
+	c.OnEvent.Publish(events.Event{Type: events.PageFetched, URL: normalizedURL, Depth: depth, StatusCode: result.StatusCode})
+
+	// Record the page, marking the redirect target as visited so it isn't fetched twice
+	c.mu.Lock()
+	c.crawled[normalizedURL] = CheckpointPage{URL: normalizedURL, Depth: depth, Source: task.Source, LinkKind: task.LinkKind, StatusCode: result.StatusCode}
+	if result.FinalURL != "" && c.isSameHost(result.FinalURL) {
+		c.visited[c.normalizeURL(result.FinalURL)] = true
 	}
+	c.mu.Unlock()
 
-	// Get page content
-	content, err := page.Content()
+	// Discover links relative to where the page actually lives; list mode
+	// audits only the listed pages
+	if !c.listMode() {
+		baseURL := normalizedURL
+		if result.FinalURL != "" {
+			baseURL = result.FinalURL
+		}
+		c.discoverLinks(c.ctx, result.Content, baseURL, depth)
+	}
+
+	// Capture the primary render before the session navigates elsewhere
+	if c.ScreenshotDir != "" && result.Content != "" {
+		result.Screenshots = c.captureScreenshots(sessions[0], normalizedURL)
+	}
+
+	// Fetch the raw HTML and render the page on the other devices so they can be compared
+	if result.StatusCode == 200 {
+		if c.raw != nil {
+			result.Raw = c.fetchRaw(normalizedURL)
+		}
+		for i, session := range sessions[1:] {
+			if render, ok := c.renderOn(session, c.Devices[i+1], normalizedURL); ok {
+				result.Renders = append(result.Renders, render)
+			}
+		}
+	}
+
+	c.emit(*result)
+}
+
+// renderOn fetches a page again on another device. It returns false when the
+// crawl was interrupted, as the page is still reported with its primary render.
+func (c *Crawler) renderOn(session Session, device Device, pageURL string) (DeviceRender, bool) {
+	result, err := c.fetchPolitely(session, pageURL)
 	if err != nil {
-		content = "" // Empty content but still record the page with its status code
+		if c.ctx.Err() != nil {
+			return DeviceRender{}, false
+		}
+		return DeviceRender{Device: device.Name, Error: err.Error()}, true
 	}
 
-	// Store result
+	return DeviceRender{
+		Device:     device.Name,
+		Content:    result.Content,
+		StatusCode: result.StatusCode,
+		Viewport:   result.Viewport,
+	}, true
+}
+
+// emit hands a fetched page to the consumer of Pages. It blocks while the
+// consumer is busy, which keeps the number of pages held in memory bounded.
+func (c *Crawler) emit(result PageResult) {
+	c.pages <- result
+
 	c.mu.Lock()
-	c.results = append(c.results, PageResult{
-		URL:        normalizedURL,
-		Content:    content,
-		Depth:      depth,
-		StatusCode: statusCode,
-	})
+	c.emitted++
 	c.mu.Unlock()
-
-	// Discover links if we haven't reached max depth
-	if c.MaxDepth == 0 || depth < c.MaxDepth {
-		c.discoverLinks(page, normalizedURL, depth, ctx)
-	}
 }
 
-func (c *Crawler) discoverLinks(page playwright.Page, pageURL string, depth int, ctx context.Context) {
+func (c *Crawler) discoverLinks(ctx context.Context, content string, pageURL string, depth int) {
 	select {
 	case <-ctx.Done():
 		return
@@ -361,15 +468,20 @@ func (c *Crawler) discoverLinks(page playwright.Page, pageURL string, depth int,
 	default:
 	}
 
-	anchors, err := page.QuerySelectorAll("a")
+	if content == "" {
+		return
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
 	if err != nil {
 		return
 	}
 
 	linksFound := 0
 	linksQueued := 0
+	canFollow := c.MaxDepth == 0 || depth < c.MaxDepth
 
-	for _, a := range anchors {
+	for _, link := range extractLinks(doc) {
 		select {
 		case <-ctx.Done():
 			return
@@ -378,12 +490,7 @@ func (c *Crawler) discoverLinks(page playwright.Page, pageURL string, depth int,
 		default:
 		}
 
-		href, _ := a.GetAttribute("href")
-		if href == "" {
-			continue
-		}
-
-		abs := resolveURL(pageURL, href)
+		abs := resolveURL(pageURL, link.href)
 		if abs == "" {
 			continue
 		}
@@ -391,66 +498,51 @@ func (c *Crawler) discoverLinks(page playwright.Page, pageURL string, depth int,
 		normalizedAbs := c.normalizeURL(abs)
 		linksFound++
 
-		// Check if URL is valid for crawling
-		if c.isSameHost(normalizedAbs) && !c.shouldIgnore(normalizedAbs) && !c.isDisallowedByRobots(normalizedAbs) {
-			c.mu.RLock()
-			alreadyVisited := c.visited[normalizedAbs]
-			c.mu.RUnlock()
-
-			if !alreadyVisited {
-				if c.addToQueue(crawlTask{URL: normalizedAbs, Depth: depth + 1}) {
-					linksQueued++
-				}
+		if !c.isInScope(normalizedAbs) {
+			if c.isSameHost(normalizedAbs) {
+				c.mu.Lock()
+				c.recordSkip(normalizedAbs, SkipOutOfScope)
+				c.mu.Unlock()
 			}
+			continue
 		}
-	}
-}
 
-// normalizeURL removes fragments and trailing slashes, and converts to lowercase
-func (c *Crawler) normalizeURL(rawURL string) string {
-	parsed, err := url.Parse(rawURL)
-	if err != nil {
-		return rawURL
-	}
-
-	// Remove fragment
-	parsed.Fragment = ""
-
-	// Handle root path consistently - always use "/" for root
-	if parsed.Path == "" {
-		parsed.Path = "/"
-	} else if parsed.Path != "/" && strings.HasSuffix(parsed.Path, "/") {
-		// Remove trailing slash from non-root paths
-		parsed.Path = strings.TrimSuffix(parsed.Path, "/")
-	}
-
-	// Convert host to lowercase
-	parsed.Host = strings.ToLower(parsed.Host)
-
-	return parsed.String()
-}
+		// Remember internal links for sitemap coverage reporting; a page only
+		// referenced by canonicals or alternates is still unlinked for users
+		if link.navigational() {
+			c.mu.Lock()
+			c.linkedURLs[normalizedAbs] = true
+			c.mu.Unlock()
+		}
 
-func (c *Crawler) shouldIgnore(url string) bool {
-	if len(c.IgnorePatterns) == 0 {
-		return false
-	}
+		c.mu.RLock()
+		alreadyVisited := c.visited[normalizedAbs]
+		c.mu.RUnlock()
+		if alreadyVisited {
+			continue
+		}
 
-	for _, pattern := range c.IgnorePatterns {
-		// Try as regex first
-		if strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
-			regex := strings.Trim(pattern, "/")
-			if matched, _ := regexp.MatchString(regex, url); matched {
-				return true
-			}
+		// Check if URL is valid for crawling, remembering why it is not
+		reason := c.skipReason(normalizedAbs, false)
+		if reason == "" && !canFollow {
+			reason = SkipDepth
+		}
+		if reason != "" {
+			c.mu.Lock()
+			c.recordSkip(normalizedAbs, reason)
+			c.mu.Unlock()
+			continue
 		}
 
-		// Try as simple string contains
-		if strings.Contains(url, pattern) {
-			return true
+		if c.addToQueue(crawlTask{URL: normalizedAbs, Depth: depth + 1, Source: SourceLink, LinkKind: link.kind}) {
+			linksQueued++
 		}
 	}
+}
 
-	return false
+// normalizeURL canonicalizes a URL with the crawl's URL policy
+func (c *Crawler) normalizeURL(rawURL string) string {
+	return c.URLPolicy.Normalize(rawURL)
 }
 
 func resolveURL(base, href string) string {
@@ -489,199 +581,147 @@ func (c *Crawler) isSameHost(u string) bool {
 	return strings.EqualFold(parsed.Host, baseParsed.Host)
 }
 
-// GetResults returns a copy of the results (thread-safe)
-func (c *Crawler) GetResults() []PageResult {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// isInScope checks that a URL is on the base host and under the base URL's path
+func (c *Crawler) isInScope(u string) bool {
+	if !c.isSameHost(u) {
+		return false
+	}
 
-	results := make([]PageResult, len(c.results))
-	copy(results, c.results)
-	return results
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	baseParsed, err := url.Parse(c.normalizeURL(c.BaseURL))
+	if err != nil {
+		return false
+	}
+
+	basePath := strings.TrimSuffix(baseParsed.Path, "/")
+	if basePath == "" {
+		return true
+	}
+
+	// Match whole path segments so /docs does not include /docs-old
+	return parsed.Path == basePath || strings.HasPrefix(parsed.Path, basePath+"/")
+}
+
+// fetcherOptions returns the options shared by the page fetcher and auxiliary requests
+func (c *Crawler) fetcherOptions() FetcherOptions {
+	return FetcherOptions{
+		BaseURL:        c.BaseURL,
+		Insecure:       c.Insecure,
+		Auth:           c.Auth,
+		BlockResources: c.BlockResources,
+		Profile:        c.Profile,
+	}
+}
+
+// Pages returns the channel on which fetched pages are streamed during Start
+func (c *Crawler) Pages() <-chan PageResult {
+	return c.pages
 }
 
 // GetStats returns current crawling statistics
 func (c *Crawler) GetStats() (visited int, queued int, results int) {
 	c.mu.RLock()
 	visited = len(c.visited)
-	results = len(c.results)
+	results = c.emitted
 	c.mu.RUnlock()
 
-	c.queueMu.Lock()
-	queued = len(c.queue)
-	c.queueMu.Unlock()
+	queued = c.frontier.len()
 
 	return
 }
 
-// fetchRobotsTxt fetches and parses robots.txt from the base URL
-func (c *Crawler) fetchRobotsTxt() {
-	baseURL, err := url.Parse(c.BaseURL)
-	if err != nil {
-		return // Skip robots.txt if base URL is invalid
-	}
+// CrawlStats summarizes a finished crawl
+type CrawlStats struct {
+	PagesCrawled   int           // Pages fetched, including earlier runs of a resumed crawl
+	NotCrawled     int           // URLs discovered but never crawled because MaxPages was reached
+	Duration       time.Duration // Time spent fetching pages in this run
+	PagesPerSecond float64       // Fetch throughput of this run
+	CrawlDelay     time.Duration // Crawl-delay from robots.txt that was honored
+	Throttled      int           // 429/503 responses that made the crawler back off
+	Skipped        []SkippedURL  // Same-host URLs not crawled, with the reason
+}
 
-	robotsURL := fmt.Sprintf("%s://%s/robots.txt", baseURL.Scheme, baseURL.Host)
+// GetCrawlStats returns statistics for the finished crawl
+func (c *Crawler) GetCrawlStats() CrawlStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	stats := CrawlStats{
+		PagesCrawled: c.pageCount,
+		Duration:     c.crawlTime,
+		CrawlDelay:   c.crawlDelay,
+		Throttled:    c.throttled,
+		Skipped:      c.skippedURLs(),
 	}
-
-	resp, err := client.Get(robotsURL)
-	if err != nil {
-		return // No robots.txt or error - allow all
+	if c.crawlTime > 0 {
+		stats.PagesPerSecond = float64(c.emitted) / c.crawlTime.Seconds()
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		return // No robots.txt - allow all
+	if !c.limitReached {
+		return stats
 	}
 
-	c.parseRobotsTxt(resp)
-}
-
-// parseRobotsTxt parses robots.txt content and stores rules
-func (c *Crawler) parseRobotsTxt(resp *http.Response) {
-	scanner := bufio.NewScanner(resp.Body)
-	var currentRule *robotsRule
-
-	c.robotsMu.Lock()
-	defer c.robotsMu.Unlock()
-
-	lineNum := 0
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		lineNum++
-
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Split on first colon
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
-			continue
-		}
-
-		field := strings.TrimSpace(strings.ToLower(parts[0]))
-		value := strings.TrimSpace(parts[1])
-
-		switch field {
-		case "user-agent":
-			// Save previous rule if exists
-			if currentRule != nil {
-
-				c.robotsRules = append(c.robotsRules, *currentRule)
-			}
-			// Start new rule
-			currentRule = &robotsRule{
-				userAgent: strings.ToLower(value),
-				disallow:  []string{},
-				allow:     []string{},
-			}
-
-		case "disallow":
-			if currentRule != nil && value != "" {
-				currentRule.disallow = append(currentRule.disallow, value)
-			}
-
-		case "allow":
-			if currentRule != nil && value != "" {
-				currentRule.allow = append(currentRule.allow, value)
-			}
+	stats.NotCrawled = c.overLimit
+	for _, task := range c.frontier.remaining() {
+		if !c.visited[task.URL] {
+			stats.NotCrawled++
 		}
 	}
-
-	// Save last rule
-	if currentRule != nil {
-
-		c.robotsRules = append(c.robotsRules, *currentRule)
-	}
+	return stats
 }
 
-// isDisallowedByRobots checks if a URL is disallowed by robots.txt
-func (c *Crawler) isDisallowedByRobots(urlStr string) bool {
-	c.robotsMu.RLock()
-	defer c.robotsMu.RUnlock()
-
-	if len(c.robotsRules) == 0 {
-		return false // No robots.txt rules - allow all
-	}
-
-	parsedURL, err := url.Parse(urlStr)
+// fetchRobotsTxt fetches robots.txt for the site being crawled
+func (c *Crawler) fetchRobotsTxt() {
+	client, err := newHTTPClient(c.fetcherOptions(), 10*time.Second)
 	if err != nil {
-		return false // Invalid URL - allow
+		return
 	}
 
-	path := parsedURL.Path
-	if path == "" {
-		path = "/"
+	// On a 5xx or network error, the returned rules disallow everything
+	rules, err := robots.Fetch(c.ctx, client, c.BaseURL)
+	if rules == nil {
+		return
 	}
-
-	// Check rules for Googlebot first, then * (all crawlers)
-	userAgents := []string{"googlebot", "*"}
-
-	for _, ua := range userAgents {
-		for _, rule := range c.robotsRules {
-			if rule.userAgent != ua {
-				continue
-			}
-			// Find the longest matching pattern (most specific wins)
-			var longestMatch string
-			var isDisallowed bool
-
-			// Check all Allow patterns
-			for _, allowPattern := range rule.allow {
-				if c.matchesRobotsPattern(path, allowPattern) && len(allowPattern) > len(longestMatch) {
-					longestMatch = allowPattern
-					isDisallowed = false
-				}
-			}
-
-			// Check all Disallow patterns
-			for _, disallowPattern := range rule.disallow {
-				if c.matchesRobotsPattern(path, disallowPattern) && len(disallowPattern) > len(longestMatch) {
-					longestMatch = disallowPattern
-					isDisallowed = true
-				}
-			}
-
-			// If we found a matching pattern, use it
-			if longestMatch != "" {
-				if isDisallowed {
-					return true
-				} else {
-					return false
-				}
-			}
+	if err != nil {
+		log.Printf("robots.txt: %v", err)
+		if c.RobotsFailOpen {
+			return // Crawl as if there were no robots.txt
 		}
 	}
 
-	return false // Default allow if no matching rules
+	c.robotsMu.Lock()
+	c.robots = rules
+	c.robotsMu.Unlock()
 }
 
-// matchesRobotsPattern checks if a path matches a robots.txt pattern
-func (c *Crawler) matchesRobotsPattern(path, pattern string) bool {
-	// Empty pattern means match nothing
-	if pattern == "" {
-		return false
-	}
+// Robots returns the robots.txt rules the crawl obeyed, or nil if none were fetched
+func (c *Crawler) Robots() *robots.Robots {
+	c.robotsMu.RLock()
+	defer c.robotsMu.RUnlock()
 
-	// Simple prefix matching (most common case)
-	if strings.HasPrefix(path, pattern) {
-		return true
-	}
+	return c.robots
+}
 
-	// Handle wildcards - convert robots.txt pattern to regex
-	regexPattern := regexp.QuoteMeta(pattern)
-	regexPattern = strings.ReplaceAll(regexPattern, "\\*", ".*")
-	regexPattern = "^" + regexPattern
+// robotsCrawlDelay returns the Crawl-delay robots.txt sets for RobotsAgent
+func (c *Crawler) robotsCrawlDelay() time.Duration {
+	c.robotsMu.RLock()
+	defer c.robotsMu.RUnlock()
 
-	matched, err := regexp.MatchString(regexPattern, path)
-	if err != nil {
-		// Fall back to simple prefix matching if regex fails
-		return strings.HasPrefix(path, pattern)
+	if c.robots == nil {
+		return 0
 	}
+	return c.robots.CrawlDelay(c.RobotsAgent)
+}
+
+// isDisallowedByRobots checks if a URL is disallowed by robots.txt for RobotsAgent
+func (c *Crawler) isDisallowedByRobots(urlStr string) bool {
+	c.robotsMu.RLock()
+	defer c.robotsMu.RUnlock()
 
-	return matched
+	if c.robots == nil {
+		return false // No robots.txt - allow all
+	}
+	return !c.robots.Allowed(c.RobotsAgent, urlStr)
 }
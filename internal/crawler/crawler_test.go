@@ -0,0 +1,146 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// benchmarkPages is the size of the site crawled by BenchmarkCrawl
+const benchmarkPages = 60
+
+// newBenchmarkSite serves pages / and /page/1 .. /page/n-1 as a binary tree:
+// page i links to pages 2i+1 and 2i+2, so the crawl reaches every page
+func newBenchmarkSite(n int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		index := 0
+		if r.URL.Path != "/" {
+			number, ok := strings.CutPrefix(r.URL.Path, "/page/")
+			var err error
+			if index, err = strconv.Atoi(number); !ok || err != nil || index <= 0 || index >= n {
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		var links strings.Builder
+		for _, child := range []int{2*index + 1, 2*index + 2} {
+			if child < n {
+				fmt.Fprintf(&links, `<li><a href="/page/%d">Page %d</a></li>`, child, child)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head><title>Page %d</title><meta name="description" content="Benchmark page %d"></head>
+<body><h1>Page %d</h1><p>%s</p><ul>%s</ul></body>
+</html>`, index, index, index, strings.Repeat("Lorem ipsum dolor sit amet. ", 50), links.String())
+	}))
+}
+
+// sessionPerFetch reproduces the crawler before sessions were reused: every
+// fetch opens a fresh session (a browser context and page) and closes it again
+type sessionPerFetch struct {
+	Fetcher
+}
+
+func (f sessionPerFetch) NewSession(device *Device) (Session, error) {
+	return &freshSession{fetcher: f.Fetcher, device: device}, nil
+}
+
+type freshSession struct {
+	fetcher Fetcher
+	device  *Device
+}
+
+func (s *freshSession) Fetch(ctx context.Context, pageURL string) (*PageResult, error) {
+	session, err := s.fetcher.NewSession(s.device)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	return session.Fetch(ctx, pageURL)
+}
+
+func (s *freshSession) Close() {}
+
+// crawlSite crawls the whole site and returns the number of pages fetched
+func crawlSite(b *testing.B, baseURL, renderer string, freshSessions bool) int {
+	c := NewCrawler(context.Background(), baseURL, 4, 0, 0, nil)
+	c.Renderer = renderer
+	if freshSessions {
+		fetcher, err := NewFetcher(renderer, c.fetcherOptions())
+		if err != nil {
+			b.Fatal(err)
+		}
+		c.fetcher = sessionPerFetch{fetcher}
+	}
+
+	done := make(chan int)
+	go func() {
+		pages := 0
+		for range c.Pages() {
+			pages++
+		}
+		done <- pages
+	}()
+
+	if err := c.Start(); err != nil {
+		b.Fatal(err)
+	}
+	return <-done
+}
+
+// BenchmarkCrawl compares crawl throughput with browser sessions reused by each
+// worker and with a fresh session per page; HTTP sessions hold no state, so the
+// HTTP crawl is only measured as a baseline. The browser variants need
+// Playwright and are skipped without it. Compare with:
+//
+//	go test ./internal/crawler -run '^$' -bench Crawl
+func BenchmarkCrawl(b *testing.B) {
+	site := newBenchmarkSite(benchmarkPages)
+	defer site.Close()
+
+	for _, variant := range []struct {
+		renderer string
+		mode     string
+		fresh    bool
+	}{
+		{RendererHTTP, "reused", false},
+		{RendererBrowser, "reused", false},
+		{RendererBrowser, "per-page", true},
+	} {
+		b.Run(variant.renderer+"/"+variant.mode, func(b *testing.B) {
+			if variant.renderer == RendererBrowser {
+				skipWithoutBrowser(b)
+			}
+
+			total := 0
+			for i := 0; i < b.N; i++ {
+				pages := crawlSite(b, site.URL, variant.renderer, variant.fresh)
+				if pages != benchmarkPages {
+					b.Fatalf("crawled %d pages, want %d", pages, benchmarkPages)
+				}
+				total += pages
+			}
+			b.ReportMetric(float64(total)/b.Elapsed().Seconds(), "pages/s")
+		})
+	}
+}
+
+// skipWithoutBrowser skips the benchmark when headless Chromium cannot start
+func skipWithoutBrowser(b *testing.B) {
+	fetcher, err := NewFetcher(RendererBrowser, FetcherOptions{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := fetcher.Start(); err != nil {
+		b.Skipf("browser not available: %v", err)
+	}
+	fetcher.Close()
+}
@@ -0,0 +1,81 @@
+package crawler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Emulated devices
+const (
+	DeviceMobile  = "mobile"
+	DeviceDesktop = "desktop"
+	DeviceBoth    = "both" // Render on mobile, then on desktop for parity checks
+)
+
+// Device is a screen pages can be rendered on
+type Device struct {
+	Name      string
+	Width     int // Viewport size in CSS pixels
+	Height    int
+	Mobile    bool   // Emulate a phone: meta viewport, touch events and a high-density screen
+	UserAgent string // Sent unless a profile sets the User-Agent
+}
+
+// Devices lists the devices pages can be rendered on
+var Devices = []Device{
+	{
+		Name:      DeviceMobile,
+		Width:     412,
+		Height:    915,
+		Mobile:    true,
+		UserAgent: "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36",
+	},
+	{
+		Name:      DeviceDesktop,
+		Width:     1366,
+		Height:    768,
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+	},
+}
+
+// ParseDevices returns the devices to render pages on for "mobile", "desktop"
+// or "both". Mobile comes first, as search engines index the mobile render.
+// An empty value means no emulation.
+func ParseDevices(value string) ([]Device, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return nil, nil
+	case DeviceBoth:
+		return Devices, nil
+	}
+
+	for _, device := range Devices {
+		if strings.EqualFold(device.Name, value) {
+			return []Device{device}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown device %q (expected %s, %s or %s)", value, DeviceMobile, DeviceDesktop, DeviceBoth)
+}
+
+// ViewportMetrics is the rendered width of a page compared with its viewport
+type ViewportMetrics struct {
+	Width       int // Viewport width in CSS pixels
+	ScrollWidth int // Width of the rendered document
+}
+
+// Overflow returns how far the page scrolls horizontally past the viewport
+func (m ViewportMetrics) Overflow() int {
+	if m.ScrollWidth <= m.Width {
+		return 0
+	}
+	return m.ScrollWidth - m.Width
+}
+
+// DeviceRender is a page as rendered on another device than the primary one
+type DeviceRender struct {
+	Device     string
+	Content    string
+	StatusCode int
+	Viewport   *ViewportMetrics // Nil unless the browser renderer measured it
+	Error      string           // Why the page could not be rendered on this device
+}
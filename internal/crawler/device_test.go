@@ -0,0 +1,58 @@
+package crawler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDevices(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "mobile", want: []string{DeviceMobile}},
+		{value: "Desktop", want: []string{DeviceDesktop}},
+		{value: "BOTH", want: []string{DeviceMobile, DeviceDesktop}}, // Mobile first, as it is indexed
+		{value: "tablet", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		devices, err := ParseDevices(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDevices(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		var names []string
+		for _, device := range devices {
+			names = append(names, device.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("ParseDevices(%q) = %v, want %v", tt.value, names, tt.want)
+		}
+	}
+
+	mobile, _ := ParseDevices(DeviceMobile)
+	if !mobile[0].Mobile || mobile[0].Width >= 600 {
+		t.Errorf("mobile device = %+v, want a phone-sized mobile viewport", mobile[0])
+	}
+}
+
+func TestViewportOverflow(t *testing.T) {
+	tests := []struct {
+		metrics ViewportMetrics
+		want    int
+	}{
+		{ViewportMetrics{Width: 412, ScrollWidth: 412}, 0},
+		{ViewportMetrics{Width: 412, ScrollWidth: 300}, 0},
+		{ViewportMetrics{Width: 412, ScrollWidth: 413}, 1},
+		{ViewportMetrics{Width: 412, ScrollWidth: 980}, 568},
+	}
+
+	for _, tt := range tests {
+		if got := tt.metrics.Overflow(); got != tt.want {
+			t.Errorf("%+v.Overflow() = %d, want %d", tt.metrics, got, tt.want)
+		}
+	}
+}
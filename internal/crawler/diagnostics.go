@@ -0,0 +1,148 @@
+package crawler
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxDiagnostics caps each kind of diagnostic recorded per page
+const maxDiagnostics = 50
+
+// PageDiagnostics holds the runtime problems seen while a page rendered in the browser
+type PageDiagnostics struct {
+	ConsoleErrors  []ConsoleError
+	PageErrors     []string // Uncaught exceptions
+	FailedRequests []FailedRequest
+}
+
+// ConsoleError is a console.error message logged by the page
+type ConsoleError struct {
+	Message  string
+	Location string // Script URL with line and column, if known
+}
+
+// FailedRequest is a subresource request that failed or got a 4xx/5xx response
+type FailedRequest struct {
+	URL          string
+	ResourceType string // script, stylesheet, image, font, fetch, ...
+	StatusCode   int    // 0 when the request failed without a response
+	Error        string // Network error, e.g. net::ERR_NAME_NOT_RESOLVED
+}
+
+// diagnosticsCollector receives page events for the fetch in progress. Handlers
+// are registered once per page; each fetch swaps in a fresh PageDiagnostics.
+type diagnosticsCollector struct {
+	mu      sync.Mutex
+	current *PageDiagnostics
+	blocked map[string]bool // Resource types aborted on purpose, not reported as failures
+}
+
+// watch registers the collector's handlers on a page
+func (d *diagnosticsCollector) watch(page playwright.Page) {
+	page.OnConsole(func(message playwright.ConsoleMessage) {
+		entry := ConsoleError{Message: message.Text()}
+		if location := message.Location(); location != nil && location.URL != "" {
+			entry.Location = fmt.Sprintf("%s:%d:%d", location.URL, location.LineNumber, location.ColumnNumber)
+		}
+		d.consoleMessage(message.Type(), entry)
+	})
+
+	page.OnPageError(func(err error) {
+		d.pageError(err.Error())
+	})
+
+	page.OnRequestFailed(func(request playwright.Request) {
+		entry := FailedRequest{URL: request.URL(), ResourceType: request.ResourceType()}
+		if err := request.Failure(); err != nil {
+			entry.Error = err.Error()
+		}
+		d.requestFailed(entry, request.IsNavigationRequest())
+	})
+
+	page.OnResponse(func(response playwright.Response) {
+		request := response.Request()
+		d.response(FailedRequest{
+			URL:          response.URL(),
+			ResourceType: request.ResourceType(),
+			StatusCode:   response.Status(),
+		}, request.IsNavigationRequest())
+	})
+}
+
+// consoleMessage records console messages of the error type
+func (d *diagnosticsCollector) consoleMessage(kind string, entry ConsoleError) {
+	if kind != "error" {
+		return
+	}
+	d.record(func(diag *PageDiagnostics) {
+		if len(diag.ConsoleErrors) < maxDiagnostics {
+			diag.ConsoleErrors = append(diag.ConsoleErrors, entry)
+		}
+	})
+}
+
+// pageError records an uncaught exception
+func (d *diagnosticsCollector) pageError(message string) {
+	d.record(func(diag *PageDiagnostics) {
+		if len(diag.PageErrors) < maxDiagnostics {
+			diag.PageErrors = append(diag.PageErrors, message)
+		}
+	})
+}
+
+// requestFailed records a subresource request that got no response. The page
+// itself failing is reported as a fetch error, and blocked resource types are
+// aborted on purpose.
+func (d *diagnosticsCollector) requestFailed(entry FailedRequest, navigation bool) {
+	if navigation || d.blocked[entry.ResourceType] {
+		return
+	}
+	d.addFailedRequest(entry)
+}
+
+// response records a subresource response with a 4xx or 5xx status
+func (d *diagnosticsCollector) response(entry FailedRequest, navigation bool) {
+	if entry.StatusCode < 400 || navigation {
+		return
+	}
+	d.addFailedRequest(entry)
+}
+
+func (d *diagnosticsCollector) addFailedRequest(entry FailedRequest) {
+	d.record(func(diag *PageDiagnostics) {
+		if len(diag.FailedRequests) < maxDiagnostics {
+			diag.FailedRequests = append(diag.FailedRequests, entry)
+		}
+	})
+}
+
+// record applies fn to the diagnostics of the current fetch; events arriving
+// between fetches are dropped
+func (d *diagnosticsCollector) record(fn func(*PageDiagnostics)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.current != nil {
+		fn(d.current)
+	}
+}
+
+// start begins collecting for a new fetch
+func (d *diagnosticsCollector) start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.current = &PageDiagnostics{}
+}
+
+// stop ends collecting and returns what the fetch produced
+func (d *diagnosticsCollector) stop() *PageDiagnostics {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	diag := d.current
+	d.current = nil
+	return diag
+}
@@ -0,0 +1,68 @@
+package crawler
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDiagnosticsCollector(t *testing.T) {
+	d := &diagnosticsCollector{blocked: map[string]bool{"image": true}}
+
+	// Events before the first fetch are dropped
+	d.pageError("early")
+	d.start()
+
+	d.consoleMessage("log", ConsoleError{Message: "hello"})
+	d.consoleMessage("warning", ConsoleError{Message: "deprecated"})
+	d.consoleMessage("error", ConsoleError{Message: "boom", Location: "http://localhost:3000/app.js:3:7"})
+	d.pageError("TypeError: x is undefined")
+
+	d.requestFailed(FailedRequest{URL: "http://localhost:3000/", ResourceType: "document", Error: "net::ERR_ABORTED"}, true)
+	d.requestFailed(FailedRequest{URL: "http://localhost:3000/logo.png", ResourceType: "image", Error: "net::ERR_FAILED"}, false)
+	d.requestFailed(FailedRequest{URL: "https://cdn.example.com/lib.js", ResourceType: "script", Error: "net::ERR_NAME_NOT_RESOLVED"}, false)
+
+	d.response(FailedRequest{URL: "http://localhost:3000/", ResourceType: "document", StatusCode: 404}, true)
+	d.response(FailedRequest{URL: "http://localhost:3000/app.css", ResourceType: "stylesheet", StatusCode: 200}, false)
+	d.response(FailedRequest{URL: "http://localhost:3000/moved.css", ResourceType: "stylesheet", StatusCode: 304}, false)
+	d.response(FailedRequest{URL: "http://localhost:3000/api", ResourceType: "fetch", StatusCode: 500}, false)
+
+	got := d.stop()
+	want := &PageDiagnostics{
+		ConsoleErrors: []ConsoleError{{Message: "boom", Location: "http://localhost:3000/app.js:3:7"}},
+		PageErrors:    []string{"TypeError: x is undefined"},
+		FailedRequests: []FailedRequest{
+			{URL: "https://cdn.example.com/lib.js", ResourceType: "script", Error: "net::ERR_NAME_NOT_RESOLVED"},
+			{URL: "http://localhost:3000/api", ResourceType: "fetch", StatusCode: 500},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostics = %+v, want %+v", got, want)
+	}
+
+	// Events between fetches are dropped, and each fetch starts empty
+	d.pageError("late")
+	d.start()
+	if got := d.stop(); !reflect.DeepEqual(got, &PageDiagnostics{}) {
+		t.Errorf("diagnostics of the next fetch = %+v, want none", got)
+	}
+}
+
+func TestDiagnosticsCollectorLimit(t *testing.T) {
+	d := &diagnosticsCollector{}
+	d.start()
+	for i := 0; i < maxDiagnostics+10; i++ {
+		d.consoleMessage("error", ConsoleError{Message: fmt.Sprint(i)})
+		d.pageError(fmt.Sprint(i))
+		d.response(FailedRequest{URL: fmt.Sprintf("http://localhost:3000/%d.js", i), StatusCode: 404}, false)
+	}
+
+	got := d.stop()
+	if len(got.ConsoleErrors) != maxDiagnostics || len(got.PageErrors) != maxDiagnostics || len(got.FailedRequests) != maxDiagnostics {
+		t.Errorf("kept %d console errors, %d page errors and %d failed requests, want %d of each",
+			len(got.ConsoleErrors), len(got.PageErrors), len(got.FailedRequests), maxDiagnostics)
+	}
+	if got.PageErrors[0] != "0" {
+		t.Errorf("first page error = %q, want the earliest kept", got.PageErrors[0])
+	}
+}
@@ -0,0 +1,111 @@
+package crawler
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Renderers supported by the crawler
+const (
+	RendererBrowser = "browser" // Headless Chromium via Playwright (renders JavaScript)
+	RendererHTTP    = "http"    // Plain net/http requests (server-rendered and static sites)
+)
+
+// Fetcher loads pages for the crawler
+type Fetcher interface {
+	// Start prepares any resources needed before pages are fetched
+	Start() error
+	// NewSession returns a session for one crawler worker, emulating device
+	// when it is not nil
+	NewSession(device *Device) (Session, error)
+	// Close releases the resources acquired by Start
+	Close()
+}
+
+// Session fetches pages for a single worker. It keeps its resources (such as a
+// browser context and page) between fetches and is not safe for concurrent use.
+type Session interface {
+	// Fetch loads the page and returns its HTML content and status code
+	Fetch(ctx context.Context, pageURL string) (*PageResult, error)
+	// Close releases the session's resources
+	Close()
+}
+
+// FetcherOptions configures how pages are requested
+type FetcherOptions struct {
+	BaseURL        string      // Base URL of the crawl, used to resolve relative login URLs and cookie domains
+	Insecure       bool        // Accept invalid TLS certificates, e.g. self-signed dev certificates
+	Auth           AuthOptions // Credentials shared by every request
+	BlockResources []string    // Resource types the browser does not load, e.g. image, font, media
+	Profile        *Profile    // Client to impersonate; nil keeps the renderer's own User-Agent and viewport
+}
+
+// BlockableResources lists the resource types that can be blocked in the browser
+var BlockableResources = []string{
+	"stylesheet", "image", "media", "font", "script", "texttrack",
+	"xhr", "fetch", "eventsource", "websocket", "manifest", "other",
+}
+
+// NewFetcher returns the fetcher for the given renderer name
+func NewFetcher(renderer string, opts FetcherOptions) (Fetcher, error) {
+	for _, resourceType := range opts.BlockResources {
+		if !slices.Contains(BlockableResources, resourceType) {
+			return nil, fmt.Errorf("unknown resource type %q (expected one of %s)", resourceType, strings.Join(BlockableResources, ", "))
+		}
+	}
+
+	switch renderer {
+	case "", RendererBrowser:
+		return &browserFetcher{opts: opts}, nil
+	case RendererHTTP:
+		if opts.Auth.LoginScript != nil {
+			return nil, fmt.Errorf("login scripts require the %q renderer", RendererBrowser)
+		}
+		return &httpFetcher{opts: opts}, nil
+	default:
+		return nil, fmt.Errorf("unknown renderer %q (expected %q or %q)", renderer, RendererHTTP, RendererBrowser)
+	}
+}
+
+// newHTTPClient returns an HTTP client that honors the fetcher options,
+// including TLS verification, the profile's User-Agent, extra headers, basic auth and cookies
+func newHTTPClient(opts FetcherOptions, timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	var base http.RoundTripper = transport
+	if opts.Profile != nil {
+		base = &userAgentTransport{base: transport, userAgent: opts.Profile.UserAgent}
+	}
+
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: base,
+	}
+
+	if opts.Auth.IsZero() {
+		return client, nil
+	}
+
+	baseURL, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	client.Transport = &authTransport{base: base, auth: opts.Auth, host: baseURL.Host}
+
+	jar, err := newCookieJar(opts.Auth, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	client.Jar = jar
+
+	return client, nil
+}
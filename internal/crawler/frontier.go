@@ -0,0 +1,174 @@
+package crawler
+
+import (
+	"sort"
+	"sync"
+)
+
+// frontier holds the URLs waiting to be crawled. Pages are handed out breadth-first:
+// a depth level only starts once every page of the previous level has been crawled,
+// and URLs within a level are crawled in sorted order, so the pages audited do not
+// depend on timing. The frontier is unbounded; nothing discovered is dropped.
+type frontier struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	levels map[int][]crawlTask  // Queued tasks by depth
+	depth  int                  // Level currently being crawled
+	sorted bool                 // Whether the current level is in URL order
+	queued map[string]bool      // URLs ever queued, so each is crawled once
+	active map[string]crawlTask // Handed out and not yet done
+	closed bool
+}
+
+func newFrontier() *frontier {
+	f := &frontier{
+		levels: make(map[int][]crawlTask),
+		queued: make(map[string]bool),
+		active: make(map[string]crawlTask),
+	}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+// push queues a task unless its URL was queued before. Tasks pushed after close
+// are kept so they are still reported as not crawled.
+func (f *frontier) push(task crawlTask) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.queued[task.URL] {
+		return false
+	}
+	f.queued[task.URL] = true
+
+	// A level that is already underway can still take shallower tasks
+	level := task.Depth
+	if level < f.depth {
+		level = f.depth
+	}
+	f.levels[level] = append(f.levels[level], task)
+	if level == f.depth {
+		f.sorted = false
+	}
+
+	f.cond.Broadcast()
+	return true
+}
+
+// next blocks until a task can be crawled. It returns false once the frontier is
+// closed or every level has been crawled.
+func (f *frontier) next() (crawlTask, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for {
+		if f.closed {
+			return crawlTask{}, false
+		}
+
+		if level := f.levels[f.depth]; len(level) > 0 {
+			if !f.sorted {
+				sort.Slice(level, func(i, j int) bool { return level[i].URL < level[j].URL })
+				f.sorted = true
+			}
+			task := level[0]
+			f.levels[f.depth] = level[1:]
+			f.active[task.URL] = task
+			return task, true
+		}
+
+		// Pages of the current level may still discover links for the next one
+		if len(f.active) > 0 {
+			f.cond.Wait()
+			continue
+		}
+
+		delete(f.levels, f.depth)
+		nextDepth, ok := f.lowestLevel()
+		if !ok {
+			f.closed = true
+			f.cond.Broadcast()
+			return crawlTask{}, false
+		}
+		f.depth = nextDepth
+		f.sorted = false
+	}
+}
+
+// done marks a task handed out by next as finished
+func (f *frontier) done(task crawlTask) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.active, task.URL)
+	f.cond.Broadcast()
+}
+
+// requeue puts back a task handed out by next that could not be crawled
+func (f *frontier) requeue(task crawlTask) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.active, task.URL)
+	f.levels[task.Depth] = append(f.levels[task.Depth], task)
+	f.cond.Broadcast()
+}
+
+// close stops handing out tasks and wakes up waiting workers
+func (f *frontier) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	f.cond.Broadcast()
+}
+
+// len returns the number of queued tasks
+func (f *frontier) len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for _, level := range f.levels {
+		n += len(level)
+	}
+	return n
+}
+
+// remaining returns the queued tasks that were never handed out
+func (f *frontier) remaining() []crawlTask {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.queuedTasks()
+}
+
+// snapshot returns every task not yet finished: queued ones and those being crawled
+func (f *frontier) snapshot() []crawlTask {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tasks := f.queuedTasks()
+	for _, task := range f.active {
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+func (f *frontier) queuedTasks() []crawlTask {
+	var tasks []crawlTask
+	for _, level := range f.levels {
+		tasks = append(tasks, level...)
+	}
+	return tasks
+}
+
+func (f *frontier) lowestLevel() (int, bool) {
+	lowest, found := 0, false
+	for depth, level := range f.levels {
+		if len(level) > 0 && (!found || depth < lowest) {
+			lowest, found = depth, true
+		}
+	}
+	return lowest, found
+}
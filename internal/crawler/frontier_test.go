@@ -0,0 +1,211 @@
+package crawler
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrontierOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		links map[string][]string // Links found on each page
+		want  []string
+	}{
+		{
+			name: "levels in sorted order",
+			links: map[string][]string{
+				"/":  {"/c", "/a", "/b"},
+				"/a": {"/a/2", "/a/1"},
+				"/c": {"/c/1"},
+			},
+			want: []string{"/", "/a", "/b", "/c", "/a/1", "/a/2", "/c/1"},
+		},
+		{
+			name: "duplicates crawled once",
+			links: map[string][]string{
+				"/":  {"/a", "/b", "/a"},
+				"/a": {"/", "/b", "/shared"},
+				"/b": {"/shared", "/a"},
+			},
+			want: []string{"/", "/a", "/b", "/shared"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFrontier()
+			f.push(crawlTask{URL: "/"})
+
+			var got []string
+			for {
+				task, ok := f.next()
+				if !ok {
+					break
+				}
+				got = append(got, task.URL)
+				for _, link := range tt.links[task.URL] {
+					f.push(crawlTask{URL: link, Depth: task.Depth + 1})
+				}
+				f.done(task)
+			}
+
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("crawl order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrontierPush(t *testing.T) {
+	f := newFrontier()
+	if !f.push(crawlTask{URL: "/a"}) {
+		t.Error("push(/a) = false on first push")
+	}
+	if f.push(crawlTask{URL: "/a", Depth: 3}) {
+		t.Error("push(/a) = true on second push")
+	}
+
+	task, _ := f.next()
+	f.done(task)
+	if f.push(crawlTask{URL: "/a"}) {
+		t.Error("push(/a) = true after it was crawled")
+	}
+}
+
+func TestFrontierShallowTaskJoinsCurrentLevel(t *testing.T) {
+	f := newFrontier()
+	f.push(crawlTask{URL: "/", Depth: 0})
+	root, _ := f.next()
+	f.push(crawlTask{URL: "/b", Depth: 1})
+	f.done(root)
+
+	first, _ := f.next() // Level 1 is now underway
+	f.push(crawlTask{URL: "/a", Depth: 0})
+	f.push(crawlTask{URL: "/c", Depth: 2})
+
+	second, ok := f.next()
+	if !ok || second.URL != "/a" {
+		t.Fatalf("next() = %q, want the shallow /a crawled with level 1", second.URL)
+	}
+	if second.Depth != 0 {
+		t.Errorf("/a depth = %d, want its own depth 0 kept", second.Depth)
+	}
+	f.done(first)
+	f.done(second)
+
+	third, _ := f.next()
+	if third.URL != "/c" {
+		t.Errorf("next() = %q, want /c", third.URL)
+	}
+}
+
+func TestFrontierWaitsForLevel(t *testing.T) {
+	f := newFrontier()
+	f.push(crawlTask{URL: "/"})
+	root, _ := f.next()
+
+	next := make(chan crawlTask)
+	go func() {
+		task, _ := f.next()
+		next <- task
+	}()
+
+	// The level is empty but / is still being crawled and may find links
+	select {
+	case task := <-next:
+		t.Fatalf("next() returned %q while the previous level was unfinished", task.URL)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	f.push(crawlTask{URL: "/a", Depth: 1})
+	f.done(root)
+
+	select {
+	case task := <-next:
+		if task.URL != "/a" {
+			t.Errorf("next() = %q, want /a", task.URL)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("next() did not return once the level was done")
+	}
+}
+
+func TestFrontierExhausted(t *testing.T) {
+	f := newFrontier()
+	f.push(crawlTask{URL: "/"})
+	root, _ := f.next()
+
+	finished := make(chan bool)
+	go func() {
+		_, ok := f.next()
+		finished <- ok
+	}()
+
+	f.done(root)
+	select {
+	case ok := <-finished:
+		if ok {
+			t.Error("next() = true with nothing left to crawl")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("next() did not return once the crawl was exhausted")
+	}
+}
+
+func TestFrontierClose(t *testing.T) {
+	f := newFrontier()
+	f.push(crawlTask{URL: "/"})
+	root, _ := f.next()
+	f.push(crawlTask{URL: "/b", Depth: 1})
+	f.push(crawlTask{URL: "/a", Depth: 1})
+
+	f.close()
+	if _, ok := f.next(); ok {
+		t.Error("next() = true after close")
+	}
+
+	// Tasks pushed after close are still reported as not crawled
+	f.push(crawlTask{URL: "/c", Depth: 2})
+	if n := f.len(); n != 3 {
+		t.Errorf("len() = %d, want 3", n)
+	}
+	if got := taskURLs(f.remaining()); got != "/a /b /c" {
+		t.Errorf("remaining() = %s, want /a /b /c", got)
+	}
+	if got := taskURLs(f.snapshot()); got != "/ /a /b /c" {
+		t.Errorf("snapshot() = %s, want the active / included", got)
+	}
+
+	f.done(root)
+	if got := taskURLs(f.snapshot()); got != "/a /b /c" {
+		t.Errorf("snapshot() = %s after done, want /a /b /c", got)
+	}
+}
+
+func TestFrontierRequeue(t *testing.T) {
+	f := newFrontier()
+	f.push(crawlTask{URL: "/"})
+	root, _ := f.next()
+	f.requeue(root)
+
+	task, ok := f.next()
+	if !ok || task.URL != "/" {
+		t.Fatalf("next() = %q, %v, want the requeued /", task.URL, ok)
+	}
+	f.done(task)
+	if _, ok := f.next(); ok {
+		t.Error("next() = true after the requeued task was done")
+	}
+}
+
+// taskURLs returns the sorted URLs of tasks, joined by spaces
+func taskURLs(tasks []crawlTask) string {
+	var urls []string
+	for _, task := range tasks {
+		urls = append(urls, task.URL)
+	}
+	sort.Strings(urls)
+	return strings.Join(urls, " ")
+}
@@ -0,0 +1,151 @@
+package crawler
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxHTTPBodyBytes caps how much of a response body the HTTP fetcher reads
+const maxHTTPBodyBytes = 10 * 1024 * 1024
+
+// httpFetcher fetches raw HTML with net/http, without executing JavaScript
+type httpFetcher struct {
+	opts   FetcherOptions
+	client *http.Client
+}
+
+func (f *httpFetcher) Start() error {
+	client, err := newHTTPClient(f.opts, 15*time.Second)
+	if err != nil {
+		return fmt.Errorf("could not set up HTTP client: %w", err)
+	}
+	f.client = client
+	return nil
+}
+
+func (f *httpFetcher) Close() {
+	f.client.CloseIdleConnections()
+}
+
+// NewSession returns a session sharing the fetcher's client, whose connection
+// pool is already safe for concurrent use
+func (f *httpFetcher) NewSession(device *Device) (Session, error) {
+	return httpSession{fetcher: f, device: device}, nil
+}
+
+// httpSession fetches pages with the shared HTTP client. Without a browser a
+// device can only be emulated through its User-Agent.
+type httpSession struct {
+	fetcher *httpFetcher
+	device  *Device
+}
+
+func (s httpSession) Fetch(ctx context.Context, pageURL string) (*PageResult, error) {
+	if s.device == nil {
+		return s.fetcher.fetch(ctx, pageURL, "")
+	}
+
+	userAgent := ""
+	if s.fetcher.opts.Profile == nil {
+		userAgent = s.device.UserAgent
+	}
+	result, err := s.fetcher.fetch(ctx, pageURL, userAgent)
+	if result != nil {
+		result.Device = s.device.Name
+	}
+	return result, err
+}
+
+func (s httpSession) Close() {}
+
+func (f *httpFetcher) fetch(ctx context.Context, pageURL, userAgent string) (*PageResult, error) {
+	header := http.Header{}
+	if userAgent != "" {
+		header.Set("User-Agent", userAgent)
+	}
+	header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+	// Ask for compression explicitly so Content-Encoding stays visible to the checks
+	header.Set("Accept-Encoding", "gzip, deflate")
+
+	resp, hops, loop, err := followRedirects(ctx, f.client, pageURL, header)
+	if loop {
+		return redirectLoopResult(pageURL, hops), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp)
+	if err != nil {
+		body = nil // Empty content but still record the page with its status code
+	}
+
+	return &PageResult{
+		URL:        pageURL,
+		Content:    string(body),
+		StatusCode: resp.StatusCode,
+		FinalURL:   resp.Request.URL.String(),
+		Redirects:  hops,
+		Headers:    flattenHeaders(resp.Header),
+	}, nil
+}
+
+// readBody reads a response body, decoding the content encodings we asked for
+func readBody(resp *http.Response) ([]byte, error) {
+	var body io.Reader = resp.Body
+
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer gz.Close()
+		body = gz
+	case "deflate":
+		zr, err := zlib.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deflate body: %w", err)
+		}
+		defer zr.Close()
+		body = zr
+	}
+
+	return io.ReadAll(io.LimitReader(body, maxHTTPBodyBytes))
+}
+
+// flattenHeaders lowercases header names and joins repeated values,
+// matching the shape Playwright reports for browser responses
+func flattenHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		separator := ", "
+		if strings.EqualFold(name, "Set-Cookie") {
+			separator = "\n"
+		}
+		headers[strings.ToLower(name)] = strings.Join(values, separator)
+	}
+	return headers
+}
+
+// redirectLoopResult records a page whose redirects never reach a final URL
+func redirectLoopResult(pageURL string, hops []RedirectHop) *PageResult {
+	statusCode := 0
+	if len(hops) > 0 {
+		statusCode = hops[len(hops)-1].StatusCode
+	}
+
+	return &PageResult{
+		URL:          pageURL,
+		StatusCode:   statusCode,
+		Redirects:    hops,
+		RedirectLoop: true,
+	}
+}
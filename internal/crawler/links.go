@@ -0,0 +1,81 @@
+package crawler
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Kinds of link a page can be discovered through
+const (
+	LinkAnchor    = "a"         // <a href>
+	LinkArea      = "area"      // <area href> in an image map
+	LinkIframe    = "iframe"    // <iframe src>
+	LinkCanonical = "canonical" // <link rel=canonical>
+	LinkNext      = "next"      // <link rel=next>, pagination
+	LinkPrev      = "prev"      // <link rel=prev>, pagination
+	LinkAlternate = "alternate" // <link rel=alternate>, e.g. a separate mobile page
+	LinkHreflang  = "hreflang"  // <link rel=alternate hreflang>, a translation
+)
+
+// pageLink is a URL referenced by a page, before it is resolved
+type pageLink struct {
+	href string
+	kind string
+}
+
+// navigational reports whether users can follow the link, as opposed to hints
+// for crawlers such as canonicals and alternates
+func (l pageLink) navigational() bool {
+	return l.kind == LinkAnchor || l.kind == LinkArea
+}
+
+// extractLinks returns the links of a page that lead to other pages: anchors,
+// image map areas, iframes and <link> elements for canonicals, pagination and
+// alternates. Alternate feeds and other non-HTML documents are skipped.
+func extractLinks(doc *goquery.Document) []pageLink {
+	var links []pageLink
+	add := func(href, kind string) {
+		if href = strings.TrimSpace(href); href != "" {
+			links = append(links, pageLink{href: href, kind: kind})
+		}
+	}
+
+	for _, a := range doc.Find("a[href]").EachIter() {
+		add(a.AttrOr("href", ""), LinkAnchor)
+	}
+	for _, area := range doc.Find("area[href]").EachIter() {
+		add(area.AttrOr("href", ""), LinkArea)
+	}
+	for _, iframe := range doc.Find("iframe[src]").EachIter() {
+		add(iframe.AttrOr("src", ""), LinkIframe)
+	}
+
+	for _, link := range doc.Find("link[rel][href]").EachIter() {
+		href := link.AttrOr("href", "")
+		for _, rel := range strings.Fields(strings.ToLower(link.AttrOr("rel", ""))) {
+			switch rel {
+			case "canonical":
+				add(href, LinkCanonical)
+			case "next":
+				add(href, LinkNext)
+			case "prev", "previous":
+				add(href, LinkPrev)
+			case "alternate":
+				if _, ok := link.Attr("hreflang"); ok {
+					add(href, LinkHreflang)
+				} else if isHTMLType(link.AttrOr("type", "")) {
+					add(href, LinkAlternate)
+				}
+			}
+		}
+	}
+
+	return links
+}
+
+// isHTMLType reports whether a <link type> refers to an HTML page; no type means HTML
+func isHTMLType(mediaType string) bool {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == "" || mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
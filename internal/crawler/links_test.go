@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestExtractLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want []string // "kind href"
+	}{
+		{
+			name: "anchors",
+			html: `<a href="/a">A</a><a href="  /b  ">B</a><a href="">Empty</a><a>No href</a><a href="https://example.com/">Out</a>`,
+			want: []string{"a /a", "a /b", "a https://example.com/"},
+		},
+		{
+			name: "image map and iframe",
+			html: `<map><area href="/region" shape="rect"><area shape="default"></map><iframe src="/embed"></iframe><iframe></iframe>`,
+			want: []string{"area /region", "iframe /embed"},
+		},
+		{
+			name: "canonical and pagination",
+			html: `<link rel="canonical" href="/page"><link rel="next" href="/page/3"><link rel="prev" href="/page/1"><link rel="Previous" href="/page/0">`,
+			want: []string{"canonical /page", "next /page/3", "prev /page/1", "prev /page/0"},
+		},
+		{
+			name: "several rel values",
+			html: `<link rel="canonical next" href="/both">`,
+			want: []string{"canonical /both", "next /both"},
+		},
+		{
+			name: "alternates",
+			html: `<link rel="alternate" href="/m/page" media="only screen and (max-width: 640px)">` +
+				`<link rel="alternate" hreflang="fr" href="/fr/page">` +
+				`<link rel="alternate" type="text/html" href="/print">` +
+				`<link rel="alternate" type="application/rss+xml" href="/feed.xml">` +
+				`<link rel="alternate" type="application/atom+xml" hreflang="en" href="/en/feed">`,
+			want: []string{"alternate /m/page", "hreflang /fr/page", "alternate /print", "hreflang /en/feed"},
+		},
+		{
+			name: "other link elements",
+			html: `<link rel="stylesheet" href="/app.css"><link rel="icon" href="/favicon.ico"><link rel="preload" href="/font.woff2"><link rel="canonical">`,
+		},
+		{
+			name: "kinds are grouped",
+			html: `<iframe src="/embed"></iframe><a href="/a">A</a><link rel="canonical" href="/c"><area href="/r">`,
+			want: []string{"a /a", "area /r", "iframe /embed", "canonical /c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head></head><body>" + tt.html + "</body></html>"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, link := range extractLinks(doc) {
+				got = append(got, fmt.Sprintf("%s %s", link.kind, link.href))
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("extractLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkNavigational(t *testing.T) {
+	for kind, want := range map[string]bool{
+		LinkAnchor: true, LinkArea: true, LinkIframe: false, LinkCanonical: false,
+		LinkNext: false, LinkPrev: false, LinkAlternate: false, LinkHreflang: false,
+	} {
+		if got := (pageLink{href: "/", kind: kind}).navigational(); got != want {
+			t.Errorf("navigational() for %s = %v, want %v", kind, got, want)
+		}
+	}
+}
+
+func TestIsHTMLType(t *testing.T) {
+	for mediaType, want := range map[string]bool{
+		"":                      true,
+		"text/html":             true,
+		" Text/HTML ":           true,
+		"application/xhtml+xml": true,
+		"application/rss+xml":   false,
+		"application/pdf":       false,
+	} {
+		if got := isHTMLType(mediaType); got != want {
+			t.Errorf("isHTMLType(%q) = %v, want %v", mediaType, got, want)
+		}
+	}
+}
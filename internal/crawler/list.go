@@ -0,0 +1,75 @@
+package crawler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ReadURLList reads the URLs to audit in list mode, one per line. Entries are
+// resolved against baseURL like links, so /blog is relative to the host and
+// blog to the base path; blank lines and lines starting with # are skipped.
+// URLs on another host than baseURL, or outside its path, are rejected.
+func ReadURLList(r io.Reader, baseURL string) ([]string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	basePath := strings.TrimSuffix(base.Path, "/")
+
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		resolved, err := base.Parse(entry)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid URL %q: %w", line, entry, err)
+		}
+		if resolved.Scheme != "http" && resolved.Scheme != "https" {
+			return nil, fmt.Errorf("line %d: %q is not an http or https URL", line, entry)
+		}
+		if !strings.EqualFold(resolved.Host, base.Host) {
+			return nil, fmt.Errorf("line %d: %s is not on %s", line, resolved, base.Host)
+		}
+		// Match whole path segments, as the crawl scope does
+		if basePath != "" && resolved.Path != basePath && !strings.HasPrefix(resolved.Path, basePath+"/") {
+			return nil, fmt.Errorf("line %d: %s is outside %s", line, resolved, baseURL)
+		}
+		urls = append(urls, resolved.String())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URL list: %w", err)
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("URL list is empty")
+	}
+
+	return urls, nil
+}
+
+// ReadURLFile reads the list-mode URLs from a file, or from stdin for "-"
+func ReadURLFile(path, baseURL string) ([]string, error) {
+	if path == "-" {
+		return ReadURLList(os.Stdin, baseURL)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadURLList(file, baseURL)
+}
+
+// listMode reports whether the crawler audits an explicit list of URLs
+func (c *Crawler) listMode() bool {
+	return len(c.URLs) > 0
+}
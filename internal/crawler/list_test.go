@@ -0,0 +1,149 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadURLList(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		list    string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "paths and URLs",
+			base: "http://localhost:3000",
+			list: "/\n/pricing\nhttp://localhost:3000/blog?page=2\nabout\n",
+			want: []string{
+				"http://localhost:3000/", "http://localhost:3000/pricing",
+				"http://localhost:3000/blog?page=2", "http://localhost:3000/about",
+			},
+		},
+		{
+			name: "blank lines and comments",
+			base: "http://localhost:3000",
+			list: "# Landing pages\n\n  /a  \n\t\n# /b\n/c\r\n",
+			want: []string{"http://localhost:3000/a", "http://localhost:3000/c"},
+		},
+		{
+			name: "host is case-insensitive",
+			base: "http://example.com",
+			list: "https://EXAMPLE.com/a\n",
+			want: []string{"https://EXAMPLE.com/a"},
+		},
+		{
+			name: "under the base path",
+			base: "http://localhost:3000/app/",
+			list: "blog\n/app\n/app/pricing\nhttp://localhost:3000/app/docs/intro\n",
+			want: []string{
+				"http://localhost:3000/app/blog", "http://localhost:3000/app",
+				"http://localhost:3000/app/pricing", "http://localhost:3000/app/docs/intro",
+			},
+		},
+		{
+			name:    "host-relative path outside the base path",
+			base:    "http://localhost:3000/app/",
+			list:    "/app/a\n/blog\n",
+			wantErr: "line 2: http://localhost:3000/blog is outside http://localhost:3000/app/",
+		},
+		{
+			name:    "sibling of the base path",
+			base:    "http://localhost:3000/app",
+			list:    "/app-old/a\n",
+			wantErr: "is outside",
+		},
+		{
+			name:    "other host",
+			base:    "http://localhost:3000",
+			list:    "/a\nhttps://example.com/b\n",
+			wantErr: "line 2: https://example.com/b is not on localhost:3000",
+		},
+		{
+			name:    "other port",
+			base:    "http://localhost:3000",
+			list:    "http://localhost:8080/\n",
+			wantErr: "is not on localhost:3000",
+		},
+		{
+			name:    "mailto",
+			base:    "http://localhost:3000",
+			list:    "mailto:hello@example.com\n",
+			wantErr: "line 1: \"mailto:hello@example.com\" is not an http or https URL",
+		},
+		{
+			name:    "ftp",
+			base:    "http://localhost:3000",
+			list:    "# Files\nftp://localhost:3000/file\n",
+			wantErr: "line 2: \"ftp://localhost:3000/file\" is not an http or https URL",
+		},
+		{
+			name:    "invalid URL",
+			base:    "http://localhost:3000",
+			list:    "http://[::1\n",
+			wantErr: "line 1: invalid URL",
+		},
+		{name: "empty list", base: "http://localhost:3000", list: "", wantErr: "URL list is empty"},
+		{name: "only comments", base: "http://localhost:3000", list: "# nothing yet\n\n", wantErr: "URL list is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadURLList(strings.NewReader(tt.list), tt.base)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadURLList() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("ReadURLList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadURLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(path, []byte("/a\n/b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadURLFile(path, "http://localhost:3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "http://localhost:3000/a, http://localhost:3000/b"; strings.Join(got, ", ") != want {
+		t.Errorf("ReadURLFile(file) = %v, want %s", got, want)
+	}
+
+	if _, err := ReadURLFile(filepath.Join(t.TempDir(), "missing.txt"), "http://localhost:3000"); !os.IsNotExist(err) {
+		t.Errorf("ReadURLFile(missing) error = %v, want a not-exist error", err)
+	}
+
+	t.Run("stdin", func(t *testing.T) {
+		stdin, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stdin.Close()
+		saved := os.Stdin
+		os.Stdin = stdin
+		defer func() { os.Stdin = saved }()
+
+		got, err := ReadURLFile("-", "http://localhost:3000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "http://localhost:3000/a, http://localhost:3000/b"; strings.Join(got, ", ") != want {
+			t.Errorf("ReadURLFile(-) = %v, want %s", got, want)
+		}
+	})
+}
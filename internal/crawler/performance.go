@@ -0,0 +1,92 @@
+package crawler
+
+import (
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// PerformanceMetrics are the loading metrics the browser reported for a page
+type PerformanceMetrics struct {
+	TTFB             time.Duration // Time to first byte of the document
+	DOMContentLoaded time.Duration // Navigation start to the end of DOMContentLoaded
+	Load             time.Duration // Navigation start to the end of the load event
+	LCP              time.Duration // Largest Contentful Paint; only meaningful when HasLCP
+	HasLCP           bool          // Whether the browser reported an LCP entry
+	CLS              float64       // Cumulative Layout Shift up to the load event
+	TransferBytes    int64         // Bytes transferred for the document and its subresources
+	Requests         int           // Document plus subresource requests
+}
+
+// performanceScript reads navigation and resource timing, then the buffered LCP
+// and layout-shift entries. Those are only exposed to observers; takeRecords hands
+// over the buffered ones synchronously instead of waiting for the observer task.
+// lcp is null when the browser reported none. Transfer sizes of cross-origin
+// resources without Timing-Allow-Origin count as 0.
+const performanceScript = `() => {
+	const metrics = { ttfb: 0, domContentLoaded: 0, load: 0, lcp: null, cls: 0, transferBytes: 0, requests: 1 };
+	const nav = performance.getEntriesByType('navigation')[0];
+	if (nav) {
+		metrics.ttfb = nav.responseStart - nav.startTime;
+		metrics.domContentLoaded = nav.domContentLoadedEventEnd - nav.startTime;
+		metrics.load = Math.max(nav.loadEventEnd, nav.loadEventStart) - nav.startTime;
+		metrics.transferBytes = nav.transferSize || 0;
+	}
+	for (const resource of performance.getEntriesByType('resource')) {
+		metrics.requests++;
+		metrics.transferBytes += resource.transferSize || 0;
+	}
+	const buffered = type => {
+		try {
+			const observer = new PerformanceObserver(() => {});
+			observer.observe({ type, buffered: true });
+			const entries = observer.takeRecords();
+			observer.disconnect();
+			return entries;
+		} catch (e) {
+			return [];
+		}
+	};
+	for (const entry of buffered('largest-contentful-paint')) metrics.lcp = entry.startTime;
+	for (const entry of buffered('layout-shift')) if (!entry.hadRecentInput) metrics.cls += entry.value;
+	return metrics;
+}`
+
+// measurePerformance collects the performance metrics of the loaded page
+func measurePerformance(page playwright.Page) *PerformanceMetrics {
+	value, err := page.Evaluate(performanceScript)
+	if err != nil {
+		return nil
+	}
+
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	number := func(key string) float64 {
+		switch v := values[key].(type) {
+		case float64:
+			return v
+		case int:
+			return float64(v)
+		case int64:
+			return float64(v)
+		}
+		return 0
+	}
+	millis := func(key string) time.Duration {
+		return time.Duration(number(key) * float64(time.Millisecond))
+	}
+
+	return &PerformanceMetrics{
+		TTFB:             millis("ttfb"),
+		DOMContentLoaded: millis("domContentLoaded"),
+		Load:             millis("load"),
+		LCP:              millis("lcp"),
+		HasLCP:           values["lcp"] != nil,
+		CLS:              number("cls"),
+		TransferBytes:    int64(number("transferBytes")),
+		Requests:         int(number("requests")),
+	}
+}
@@ -0,0 +1,98 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Profile describes the client a crawl impersonates: the User-Agent it sends,
+// the viewport pages are rendered in and the robots.txt group it obeys
+type Profile struct {
+	Name        string
+	Description string
+	UserAgent   string // User-Agent header, also reported by navigator.userAgent
+	Width       int    // Viewport size in CSS pixels
+	Height      int
+	Mobile      bool   // Emulate a phone: meta viewport, touch events and a high-density screen
+	RobotsAgent string // robots.txt group the profile obeys
+}
+
+// Profiles lists the built-in crawl profiles
+var Profiles = []Profile{
+	{
+		Name:        "browser",
+		Description: "Desktop Chrome, obeys the * robots.txt group",
+		UserAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		Width:       1280,
+		Height:      720,
+		RobotsAgent: "*",
+	},
+	{
+		Name:        "googlebot",
+		Description: "Googlebot desktop",
+		UserAgent:   "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; Googlebot/2.1; +http://www.google.com/bot.html) Chrome/131.0.0.0 Safari/537.36",
+		Width:       1280,
+		Height:      1024,
+		RobotsAgent: "Googlebot",
+	},
+	{
+		Name:        "googlebot-smartphone",
+		Description: "Googlebot smartphone, used for mobile-first indexing",
+		UserAgent:   "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		Width:       412,
+		Height:      732,
+		Mobile:      true,
+		RobotsAgent: "Googlebot",
+	},
+	{
+		Name:        "bingbot",
+		Description: "Bingbot desktop",
+		UserAgent:   "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm) Chrome/131.0.0.0 Safari/537.36",
+		Width:       1280,
+		Height:      1024,
+		RobotsAgent: "bingbot",
+	},
+	{
+		Name:        "gptbot",
+		Description: "OpenAI's GPTBot",
+		UserAgent:   "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko); compatible; GPTBot/1.2; +https://openai.com/gptbot",
+		Width:       1280,
+		Height:      1024,
+		RobotsAgent: "GPTBot",
+	},
+}
+
+// LookupProfile returns the built-in profile with the given name (case-insensitive)
+func LookupProfile(name string) (Profile, error) {
+	for _, profile := range Profiles {
+		if strings.EqualFold(profile.Name, name) {
+			return profile, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q (expected one of %s)", name, strings.Join(ProfileNames(), ", "))
+}
+
+// ProfileNames returns the names of the built-in profiles
+func ProfileNames() []string {
+	names := make([]string, len(Profiles))
+	for i, profile := range Profiles {
+		names[i] = profile.Name
+	}
+	return names
+}
+
+// userAgentTransport sends the profile's User-Agent unless the request already sets one
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
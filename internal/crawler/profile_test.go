@@ -0,0 +1,97 @@
+package crawler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLookupProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantAgent   string
+		wantMobile  bool
+		wantErr     bool
+		wantInError string
+	}{
+		{name: "browser", wantAgent: "*"},
+		{name: "googlebot", wantAgent: "Googlebot"},
+		{name: "Googlebot-Smartphone", wantAgent: "Googlebot", wantMobile: true},
+		{name: "BINGBOT", wantAgent: "bingbot"},
+		{name: "gptbot", wantAgent: "GPTBot"},
+		{name: "googlebot-mobile", wantErr: true, wantInError: "browser, googlebot, googlebot-smartphone, bingbot, gptbot"},
+		{name: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		profile, err := LookupProfile(tt.name)
+		if tt.wantErr {
+			if err == nil || !strings.Contains(err.Error(), tt.wantInError) {
+				t.Errorf("LookupProfile(%q) error = %v, want one listing %q", tt.name, err, tt.wantInError)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("LookupProfile(%q) error = %v", tt.name, err)
+			continue
+		}
+		if !strings.EqualFold(profile.Name, tt.name) {
+			t.Errorf("LookupProfile(%q).Name = %q", tt.name, profile.Name)
+		}
+		if profile.RobotsAgent != tt.wantAgent || profile.Mobile != tt.wantMobile {
+			t.Errorf("LookupProfile(%q) = robots %q, mobile %v, want %q, %v", tt.name, profile.RobotsAgent, profile.Mobile, tt.wantAgent, tt.wantMobile)
+		}
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, profile := range Profiles {
+		if seen[profile.Name] {
+			t.Errorf("profile %q is listed twice", profile.Name)
+		}
+		seen[profile.Name] = true
+
+		if profile.UserAgent == "" || profile.Width <= 0 || profile.Height <= 0 || profile.RobotsAgent == "" {
+			t.Errorf("profile %q is incomplete: %+v", profile.Name, profile)
+		}
+		// Crawler profiles announce the bot their robots.txt group is named after
+		if profile.RobotsAgent != "*" && !strings.Contains(strings.ToLower(profile.UserAgent), strings.ToLower(profile.RobotsAgent)) {
+			t.Errorf("profile %q obeys %q but its User-Agent does not name it", profile.Name, profile.RobotsAgent)
+		}
+	}
+}
+
+func TestUserAgentTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.UserAgent()))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &userAgentTransport{base: http.DefaultTransport, userAgent: "TestBot/1.0"}}
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "TestBot/1.0"},
+		{"Custom/2.0", "Custom/2.0"}, // A User-Agent set on the request, e.g. with --header, wins
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		if tt.header != "" {
+			req.Header.Set("User-Agent", tt.header)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != tt.want {
+			t.Errorf("User-Agent with %q set = %q, want %q", tt.header, body, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxThrottleRetries = 3                // Retries after a 429/503 before the response is kept
+	maxRetryAfter      = 2 * time.Minute  // Upper bound on a server's Retry-After
+	minThrottleBackoff = 1 * time.Second  // Backoff when the server sends no Retry-After
+	maxCrawlDelay      = 30 * time.Second // Upper bound on a robots.txt Crawl-delay
+)
+
+// hostLimiter spaces out requests to each host and lets throttled hosts pause every worker
+type hostLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration        // Minimum gap between two requests to the same host
+	next     map[string]time.Time // Earliest start of the next request per host
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{
+		interval: interval,
+		next:     make(map[string]time.Time),
+	}
+}
+
+// wait blocks until a request to host may start, or until ctx is done
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next[host]
+	if start.Before(now) {
+		start = now
+	}
+	l.next[host] = start.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// pause holds back every request to host for d
+func (l *hostLimiter) pause(host string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.next[host]) {
+		l.next[host] = until
+	}
+}
+
+// requestInterval combines the requests-per-second limit with the robots.txt Crawl-delay
+func requestInterval(rate float64, crawlDelay time.Duration) time.Duration {
+	interval := time.Duration(0)
+	if rate > 0 {
+		interval = time.Duration(float64(time.Second) / rate)
+	}
+	if crawlDelay > maxCrawlDelay {
+		crawlDelay = maxCrawlDelay
+	}
+	if crawlDelay > interval {
+		interval = crawlDelay
+	}
+	return interval
+}
+
+// isThrottled reports whether the server asked us to slow down
+func isThrottled(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
+// throttleBackoff returns how long to wait before retrying a throttled request.
+// It uses Retry-After (seconds or HTTP date) when present, and otherwise backs off exponentially.
+func throttleBackoff(retryAfter string, attempt int) time.Duration {
+	backoff := minThrottleBackoff << attempt
+
+	retryAfter = strings.TrimSpace(retryAfter)
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		backoff = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		backoff = time.Until(date)
+	}
+
+	if backoff < 0 {
+		backoff = 0
+	}
+	if backoff > maxRetryAfter {
+		backoff = maxRetryAfter
+	}
+	return backoff
+}
+
+// fetchPolitely fetches a page once the host's rate limit allows it, retrying
+// with backoff while the server answers 429 or 503
+func (c *Crawler) fetchPolitely(session Session, pageURL string) (*PageResult, error) {
+	host := ""
+	if parsed, err := url.Parse(pageURL); err == nil {
+		host = parsed.Host
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.wait(c.ctx, host); err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithTimeout(c.ctx, 15*time.Second)
+		result, err := session.Fetch(ctx, pageURL)
+		cancel()
+		if err != nil || !isThrottled(result.StatusCode) || attempt >= maxThrottleRetries {
+			return result, err
+		}
+
+		c.mu.Lock()
+		c.throttled++
+		c.mu.Unlock()
+
+		c.limiter.pause(host, throttleBackoff(result.Headers["retry-after"], attempt))
+	}
+}
@@ -0,0 +1,246 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestThrottleBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		attempt    int
+		want       time.Duration
+	}{
+		{"no header first attempt", "", 0, time.Second},
+		{"no header doubles", "", 2, 4 * time.Second},
+		{"no header capped", "", 10, maxRetryAfter},
+		{"seconds", "7", 0, 7 * time.Second},
+		{"seconds win over backoff", "1", 3, time.Second},
+		{"zero seconds", "0", 2, 0},
+		{"seconds with spaces", " 12 ", 0, 12 * time.Second},
+		{"seconds capped", "86400", 0, maxRetryAfter},
+		{"negative seconds ignored", "-5", 1, 2 * time.Second},
+		{"garbage ignored", "soon", 0, time.Second},
+		{"date in the past", "Wed, 21 Oct 2015 07:28:00 GMT", 0, 0},
+		{"date far ahead capped", time.Now().Add(24 * time.Hour).UTC().Format(http.TimeFormat), 0, maxRetryAfter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := throttleBackoff(tt.retryAfter, tt.attempt); got != tt.want {
+				t.Errorf("throttleBackoff(%q, %d) = %s, want %s", tt.retryAfter, tt.attempt, got, tt.want)
+			}
+		})
+	}
+
+	// HTTP dates have a one second resolution
+	date := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	if got := throttleBackoff(date, 0); got < 28*time.Second || got > 30*time.Second {
+		t.Errorf("throttleBackoff(%q) = %s, want about 30s", date, got)
+	}
+}
+
+func TestRequestInterval(t *testing.T) {
+	tests := []struct {
+		rate       float64
+		crawlDelay time.Duration
+		want       time.Duration
+	}{
+		{0, 0, 0},
+		{4, 0, 250 * time.Millisecond},
+		{0.5, 0, 2 * time.Second},
+		{4, time.Second, time.Second},              // The slower of the two wins
+		{0.5, time.Second, 2 * time.Second},        // The slower of the two wins
+		{0, 5 * time.Minute, maxCrawlDelay},        // Crawl-delay is capped
+		{0.01, 5 * time.Minute, 100 * time.Second}, // The rate is not
+		{0, 1500 * time.Millisecond, 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := requestInterval(tt.rate, tt.crawlDelay); got != tt.want {
+			t.Errorf("requestInterval(%v, %s) = %s, want %s", tt.rate, tt.crawlDelay, got, tt.want)
+		}
+	}
+}
+
+func TestIsThrottled(t *testing.T) {
+	for status, want := range map[int]bool{200: false, 404: false, 429: true, 500: false, 503: true} {
+		if got := isThrottled(status); got != want {
+			t.Errorf("isThrottled(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	const interval = 30 * time.Millisecond
+	ctx := context.Background()
+
+	t.Run("spaces requests to a host", func(t *testing.T) {
+		l := newHostLimiter(interval)
+		start := time.Now()
+		for i := 0; i < 4; i++ {
+			if err := l.wait(ctx, "a"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 3*interval {
+			t.Errorf("4 requests took %s, want at least %s", elapsed, 3*interval)
+		}
+	})
+
+	t.Run("hosts are independent", func(t *testing.T) {
+		l := newHostLimiter(time.Hour)
+		l.wait(ctx, "a")
+		start := time.Now()
+		if err := l.wait(ctx, "b"); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("first request to another host waited %s", elapsed)
+		}
+	})
+
+	t.Run("no interval", func(t *testing.T) {
+		l := newHostLimiter(0)
+		start := time.Now()
+		for i := 0; i < 100; i++ {
+			l.wait(ctx, "a")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("unlimited requests took %s", elapsed)
+		}
+	})
+
+	t.Run("pause holds back the host", func(t *testing.T) {
+		l := newHostLimiter(0)
+		l.pause("a", 3*interval)
+		l.pause("a", interval) // A shorter pause does not cut the first one short
+		start := time.Now()
+		if err := l.wait(ctx, "a"); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed < 3*interval {
+			t.Errorf("request after a pause waited %s, want at least %s", elapsed, 3*interval)
+		}
+	})
+
+	t.Run("cancelled wait", func(t *testing.T) {
+		l := newHostLimiter(0)
+		l.pause("a", time.Hour)
+		ctx, cancel := context.WithTimeout(ctx, interval)
+		defer cancel()
+		if err := l.wait(ctx, "a"); err == nil {
+			t.Error("wait() returned nil after ctx was cancelled")
+		}
+	})
+}
+
+func TestFetchPolitely(t *testing.T) {
+	tests := []struct {
+		name          string
+		throttled     int    // Throttled responses before the page is served
+		retryAfter    string // Retry-After sent with them
+		wantStatus    int
+		wantRequests  int32
+		wantThrottled int
+	}{
+		{name: "not throttled", wantStatus: 200, wantRequests: 1},
+		{name: "retry after seconds", throttled: 2, retryAfter: "0", wantStatus: 200, wantRequests: 3, wantThrottled: 2},
+		{name: "retry after past date", throttled: 1, retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", wantStatus: 200, wantRequests: 2, wantThrottled: 1},
+		{
+			name:          "gives up after the retries",
+			throttled:     100,
+			retryAfter:    "0",
+			wantStatus:    http.StatusTooManyRequests,
+			wantRequests:  maxThrottleRetries + 1,
+			wantThrottled: maxThrottleRetries,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(requests.Add(1)) <= tt.throttled {
+					w.Header().Set("Retry-After", tt.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				fmt.Fprint(w, "<html><body>ok</body></html>")
+			}))
+			defer server.Close()
+
+			c := NewCrawler(context.Background(), server.URL, 1, 0, 0, nil)
+			c.limiter = newHostLimiter(0)
+			fetcher := &httpFetcher{opts: c.fetcherOptions()}
+			if err := fetcher.Start(); err != nil {
+				t.Fatal(err)
+			}
+			defer fetcher.Close()
+			session, err := fetcher.NewSession(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer session.Close()
+
+			result, err := c.fetchPolitely(session, server.URL+"/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", result.StatusCode, tt.wantStatus)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("%d requests, want %d", got, tt.wantRequests)
+			}
+			if c.throttled != tt.wantThrottled {
+				t.Errorf("throttled = %d, want %d", c.throttled, tt.wantThrottled)
+			}
+		})
+	}
+}
+
+func TestCrawlDelay(t *testing.T) {
+	const delay = 40 * time.Millisecond
+	site := newBenchmarkSite(4)
+	handler := site.Config.Handler
+	site.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprintf(w, "User-agent: *\nCrawl-delay: %g\n", delay.Seconds())
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+	defer site.Close()
+
+	c := NewCrawler(context.Background(), site.URL, 4, 0, 0, nil)
+	c.Renderer = RendererHTTP
+	go func() {
+		for range c.Pages() {
+		}
+	}()
+
+	start := time.Now()
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(start)
+
+	stats := c.GetCrawlStats()
+	if stats.CrawlDelay != delay {
+		t.Errorf("CrawlDelay = %s, want %s", stats.CrawlDelay, delay)
+	}
+	if stats.PagesCrawled != 4 {
+		t.Fatalf("PagesCrawled = %d, want 4", stats.PagesCrawled)
+	}
+	// Four workers still fetch the pages one Crawl-delay apart
+	if elapsed < 3*delay {
+		t.Errorf("4 pages took %s, want at least %s", elapsed, 3*delay)
+	}
+}
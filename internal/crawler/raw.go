@@ -0,0 +1,25 @@
+package crawler
+
+// RawResponse is a page as served over HTTP, before any JavaScript runs
+type RawResponse struct {
+	Content    string
+	StatusCode int
+	Error      string // Why the raw HTML could not be fetched
+}
+
+// fetchRaw fetches the raw HTML of a page the browser has rendered. It returns
+// nil when the crawl was interrupted, as the page is still reported with its render.
+func (c *Crawler) fetchRaw(pageURL string) *RawResponse {
+	result, err := c.fetchPolitely(c.raw, pageURL)
+	if err != nil {
+		if c.ctx.Err() != nil {
+			return nil
+		}
+		return &RawResponse{Error: err.Error()}
+	}
+
+	return &RawResponse{
+		Content:    result.Content,
+		StatusCode: result.StatusCode,
+	}
+}
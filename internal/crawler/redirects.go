@@ -0,0 +1,90 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// maxRedirects is the number of hops followed before a chain is abandoned
+const maxRedirects = 10
+
+// RedirectHop is a single redirect response on the way to the final URL
+type RedirectHop struct {
+	URL        string
+	StatusCode int
+	Location   string
+}
+
+// IsRedirectStatus reports whether the status code is an HTTP redirect
+func IsRedirectStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// followRedirects requests pageURL and follows redirects one hop at a time,
+// recording each hop. The returned response belongs to the final URL and its
+// body must be closed by the caller. When a loop is detected no response is returned.
+func followRedirects(ctx context.Context, client *http.Client, pageURL string, header http.Header) (*http.Response, []RedirectHop, bool, error) {
+	// Never let the client follow redirects on its own
+	noFollow := *client
+	noFollow.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	var hops []RedirectHop
+	seen := map[string]bool{}
+	current := pageURL
+
+	for {
+		if seen[current] {
+			return nil, hops, true, nil
+		}
+		seen[current] = true
+
+		if len(hops) >= maxRedirects {
+			return nil, hops, false, fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, current, nil)
+		if err != nil {
+			return nil, hops, false, fmt.Errorf("invalid request: %w", err)
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		resp, err := noFollow.Do(req)
+		if err != nil {
+			return nil, hops, false, err
+		}
+
+		location := resp.Header.Get("Location")
+		if !IsRedirectStatus(resp.StatusCode) || location == "" {
+			return resp, hops, false, nil
+		}
+		resp.Body.Close()
+
+		hops = append(hops, RedirectHop{
+			URL:        current,
+			StatusCode: resp.StatusCode,
+			Location:   location,
+		})
+
+		next, err := url.Parse(current)
+		if err != nil {
+			return nil, hops, false, err
+		}
+		target, err := next.Parse(location)
+		if err != nil {
+			return nil, hops, false, fmt.Errorf("invalid Location header %q: %w", location, err)
+		}
+		target.Fragment = ""
+		current = target.String()
+	}
+}
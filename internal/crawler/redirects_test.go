@@ -0,0 +1,113 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFollowRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	redirect := func(path, location string, status int) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", location)
+			w.WriteHeader(status)
+		})
+	}
+	redirect("/permanent", "/final", http.StatusMovedPermanently)
+	redirect("/chain", "/chain/2", http.StatusFound)
+	redirect("/chain/2", "../permanent#section", http.StatusTemporaryRedirect) // Relative, with a fragment
+	redirect("/loop/a", "/loop/b", http.StatusFound)
+	redirect("/loop/b", "/loop/a", http.StatusFound)
+	redirect("/self", "/self", http.StatusMovedPermanently)
+	redirect("/no-location", "", http.StatusFound)
+	for i := 0; i <= maxRedirects; i++ {
+		redirect(fmt.Sprintf("/long/%d", i), fmt.Sprintf("/long/%d", i+1), http.StatusMovedPermanently)
+	}
+	mux.HandleFunc("/final", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "final ", r.Header.Get("X-Token"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path      string
+		wantHops  []string // "path status" for each hop
+		wantLoop  bool
+		wantErr   string
+		wantBody  string
+		wantFinal int
+	}{
+		{path: "/final", wantBody: "final secret", wantFinal: 200},
+		{path: "/permanent", wantHops: []string{"/permanent 301"}, wantBody: "final secret", wantFinal: 200},
+		{
+			path:      "/chain",
+			wantHops:  []string{"/chain 302", "/chain/2 307", "/permanent 301"},
+			wantBody:  "final secret",
+			wantFinal: 200,
+		},
+		{path: "/loop/a", wantHops: []string{"/loop/a 302", "/loop/b 302"}, wantLoop: true},
+		{path: "/self", wantHops: []string{"/self 301"}, wantLoop: true},
+		{path: "/no-location", wantFinal: 302}, // Nothing to follow; the redirect is the response
+		{path: "/long/0", wantErr: fmt.Sprintf("stopped after %d redirects", maxRedirects)},
+	}
+
+	header := http.Header{"X-Token": {"secret"}}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, hops, loop, err := followRedirects(context.Background(), server.Client(), server.URL+tt.path, header)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				if len(hops) != maxRedirects {
+					t.Errorf("%d hops recorded, want %d", len(hops), maxRedirects)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotHops []string
+			for _, hop := range hops {
+				gotHops = append(gotHops, fmt.Sprintf("%s %d", strings.TrimPrefix(hop.URL, server.URL), hop.StatusCode))
+			}
+			if strings.Join(gotHops, ", ") != strings.Join(tt.wantHops, ", ") {
+				t.Errorf("hops = %v, want %v", gotHops, tt.wantHops)
+			}
+			if loop != tt.wantLoop {
+				t.Errorf("loop = %v, want %v", loop, tt.wantLoop)
+			}
+
+			if tt.wantLoop {
+				if resp != nil {
+					t.Error("a loop should return no response")
+				}
+				return
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantFinal {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantFinal)
+			}
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != tt.wantBody {
+					t.Errorf("body = %q, want %q (headers are sent on every hop)", body, tt.wantBody)
+				}
+			}
+		})
+	}
+}
+
+func TestIsRedirectStatus(t *testing.T) {
+	for status, want := range map[int]bool{200: false, 301: true, 302: true, 303: true, 304: false, 307: true, 308: true, 404: false} {
+		if got := IsRedirectStatus(status); got != want {
+			t.Errorf("IsRedirectStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package crawler
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Reasons a discovered URL was not crawled
+const (
+	SkipIgnored    = "ignored"      // Matched an exclude pattern
+	SkipRobots     = "robots"       // Disallowed by robots.txt
+	SkipOutOfScope = "out-of-scope" // Outside the base path or the include patterns
+	SkipDepth      = "depth"        // Deeper than MaxDepth
+)
+
+// SkippedURL is a same-host URL that was discovered but not crawled
+type SkippedURL struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+// Pattern selects URLs for the include and exclude filters. Three forms are
+// accepted:
+//   - /regex/ matches the full URL against a regular expression (or contains it literally)
+//   - globs with * match the URL path: * within a segment, ** across segments,
+//     so /blog/** is /blog and everything under it; globs without a leading
+//     slash match in any directory, e.g. *.pdf
+//   - anything else matches URLs containing it
+type Pattern struct {
+	raw   string
+	regex *regexp.Regexp // Matched against the full URL
+	glob  *regexp.Regexp // Matched against the URL path
+}
+
+// CompilePatterns compiles include or exclude patterns once, up front
+func CompilePatterns(patterns []string) ([]Pattern, error) {
+	compiled := make([]Pattern, 0, len(patterns))
+	for _, raw := range patterns {
+		if raw == "" {
+			continue
+		}
+		pattern, err := compilePattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, pattern)
+	}
+	return compiled, nil
+}
+
+func compilePattern(raw string) (Pattern, error) {
+	pattern := Pattern{raw: raw}
+
+	switch {
+	case len(raw) > 2 && strings.HasPrefix(raw, "/") && strings.HasSuffix(raw, "/"):
+		regex, err := regexp.Compile(raw[1 : len(raw)-1])
+		if err != nil {
+			return pattern, fmt.Errorf("invalid regex pattern %q: %w", raw, err)
+		}
+		pattern.regex = regex
+	case strings.Contains(raw, "*"):
+		glob := raw
+		if !strings.HasPrefix(glob, "/") {
+			glob = "**/" + glob
+		}
+		regex, err := regexp.Compile(globToRegex(glob))
+		if err != nil {
+			return pattern, fmt.Errorf("invalid glob pattern %q: %w", raw, err)
+		}
+		pattern.glob = regex
+	}
+
+	return pattern, nil
+}
+
+// globToRegex translates a path glob into an anchored regular expression
+func globToRegex(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "/**/"):
+			b.WriteString("/(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("(?:/.*)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**/") && i == 0:
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// Match reports whether a URL matches the pattern
+func (p Pattern) Match(rawURL string) bool {
+	switch {
+	case p.regex != nil:
+		// The /regex/ form also matched literally before regexes were supported
+		return p.regex.MatchString(rawURL) || strings.Contains(rawURL, p.raw)
+	case p.glob != nil:
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return false
+		}
+		path := parsed.Path
+		if path == "" {
+			path = "/"
+		}
+		return p.glob.MatchString(path)
+	default:
+		return strings.Contains(rawURL, p.raw)
+	}
+}
+
+// String returns the pattern as it was written
+func (p Pattern) String() string {
+	return p.raw
+}
+
+// matchesAny reports whether a URL matches one of the patterns
+func matchesAny(patterns []Pattern, rawURL string) bool {
+	for _, pattern := range patterns {
+		if pattern.Match(rawURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileFilters compiles IncludePatterns and IgnorePatterns before the crawl starts
+func (c *Crawler) compileFilters() error {
+	var err error
+	if c.include, err = CompilePatterns(c.IncludePatterns); err != nil {
+		return err
+	}
+	if c.exclude, err = CompilePatterns(c.IgnorePatterns); err != nil {
+		return err
+	}
+	return nil
+}
+
+// shouldIgnore reports whether a URL matches an exclude pattern
+func (c *Crawler) shouldIgnore(u string) bool {
+	return matchesAny(c.exclude, u)
+}
+
+// isIncluded reports whether a URL matches the include patterns; without any,
+// every URL is included
+func (c *Crawler) isIncluded(u string) bool {
+	return len(c.include) == 0 || matchesAny(c.include, u)
+}
+
+// skipReason returns why an in-scope URL would not be crawled, or "". The seed
+// is crawled even if it is not included, as links are discovered from it, and
+// so are URLs listed explicitly.
+func (c *Crawler) skipReason(u string, explicit bool) string {
+	switch {
+	case !explicit && !c.isIncluded(u):
+		return SkipOutOfScope
+	case c.shouldIgnore(u):
+		return SkipIgnored
+	case c.isDisallowedByRobots(u):
+		return SkipRobots
+	}
+	return ""
+}
+
+// recordSkip remembers why a same-host URL was not crawled. The first reason
+// is kept; the caller must hold c.mu.
+func (c *Crawler) recordSkip(u, reason string) {
+	if _, ok := c.skipped[u]; !ok {
+		c.skipped[u] = reason
+	}
+}
+
+// skippedURLs lists the skipped URLs that were not crawled after all, e.g.
+// because a shorter path to them was found later. The caller must hold c.mu.
+func (c *Crawler) skippedURLs() []SkippedURL {
+	var skipped []SkippedURL
+	for u, reason := range c.skipped {
+		if _, crawled := c.crawled[u]; crawled {
+			continue
+		}
+		skipped = append(skipped, SkippedURL{URL: u, Reason: reason})
+	}
+	sort.Slice(skipped, func(i, j int) bool {
+		if skipped[i].Reason != skipped[j].Reason {
+			return skipped[i].Reason < skipped[j].Reason
+		}
+		return skipped[i].URL < skipped[j].URL
+	})
+	return skipped
+}
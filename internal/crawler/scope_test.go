@@ -0,0 +1,186 @@
+package crawler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ugolbck/seofordev/internal/robots"
+)
+
+func TestGlobToRegex(t *testing.T) {
+	tests := []struct {
+		glob  string
+		match []string
+		miss  []string
+	}{
+		{
+			glob:  "/blog/**",
+			match: []string{"/blog", "/blog/", "/blog/post", "/blog/2024/01/post"},
+			miss:  []string{"/blogger", "/news/blog", "/"},
+		},
+		{
+			glob:  "/blog/*",
+			match: []string{"/blog/post", "/blog/"},
+			miss:  []string{"/blog", "/blog/2024/post"},
+		},
+		{
+			glob:  "/docs/**/api",
+			match: []string{"/docs/api", "/docs/v1/api", "/docs/v1/beta/api"},
+			miss:  []string{"/docs/apis", "/docs/v1/api/users", "/api"},
+		},
+		{
+			glob:  "**/*.pdf",
+			match: []string{"/a.pdf", "/files/2024/report.pdf", "/.pdf"},
+			miss:  []string{"/a.pdf.html", "/pdf", "/files/apdf"},
+		},
+		{
+			glob:  "/*.html",
+			match: []string{"/index.html", "/.html"},
+			miss:  []string{"/xhtml", "/docs/index.html"},
+		},
+		{
+			glob:  "/a**z",
+			match: []string{"/az", "/a/b/c/z", "/abcz"},
+			miss:  []string{"/a/z/", "/za"},
+		},
+		{
+			glob:  "/search?q=*",
+			match: []string{"/search?q=go"},
+			miss:  []string{"/searchXq=go"},
+		},
+	}
+
+	for _, tt := range tests {
+		pattern, err := compilePattern(tt.glob)
+		if err != nil {
+			t.Fatalf("compilePattern(%q): %v", tt.glob, err)
+		}
+		if pattern.glob == nil {
+			t.Fatalf("compilePattern(%q) is not a glob", tt.glob)
+		}
+		for _, path := range tt.match {
+			if !pattern.glob.MatchString(path) {
+				t.Errorf("glob %q should match %q (regex %s)", tt.glob, path, pattern.glob)
+			}
+		}
+		for _, path := range tt.miss {
+			if pattern.glob.MatchString(path) {
+				t.Errorf("glob %q should not match %q (regex %s)", tt.glob, path, pattern.glob)
+			}
+		}
+	}
+}
+
+func TestPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		want    bool
+	}{
+		// Substrings match anywhere in the URL
+		{"/api", "http://localhost:3000/api/users", true},
+		{"/api", "http://localhost:3000/docs/api", true},
+		{"/api", "http://localhost:3000/apps", false},
+		{"?preview=", "http://localhost:3000/post?preview=1", true},
+
+		// Globs match the path only
+		{"*.pdf", "http://localhost:3000/files/report.pdf", true},
+		{"*.pdf", "http://localhost:3000/report.pdf?download=1", true},
+		{"*.pdf", "http://localhost:3000/report.pdf.html", false},
+		{"/blog/**", "http://localhost:3000/blog", true},
+		{"/blog/**", "http://localhost:3000/blog/post#comments", true},
+		{"/blog/**", "http://localhost:3000/blogroll", false},
+		{"/blog/tag/**", "http://localhost:3000/blog/tag/go", true},
+		{"/blog/tag/**", "http://localhost:3000/blog/post?tag=go", false},
+		{"/*", "http://localhost:3000", true}, // An empty path is /
+
+		// /regex/ matches the full URL
+		{`/\.pdf$/`, "http://localhost:3000/a.pdf", true},
+		{`/\.pdf$/`, "http://localhost:3000/a.pdf?x=1", false},
+		{`/^https:/`, "http://localhost:3000/", false},
+		{`/[0-9]{4}/`, "http://localhost:3000/archive/2024/", true},
+		{"/admin/", "http://localhost:3000/admin/users", true}, // Literal match of the /regex/ form
+	}
+
+	for _, tt := range tests {
+		patterns, err := CompilePatterns([]string{tt.pattern})
+		if err != nil {
+			t.Fatalf("CompilePatterns(%q): %v", tt.pattern, err)
+		}
+		if got := patterns[0].Match(tt.url); got != tt.want {
+			t.Errorf("%q.Match(%q) = %v, want %v", tt.pattern, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestCompilePatterns(t *testing.T) {
+	patterns, err := CompilePatterns([]string{"", "/api", "", "*.pdf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 2 {
+		t.Errorf("got %d patterns, want empty ones skipped", len(patterns))
+	}
+
+	if _, err := CompilePatterns([]string{"/[a-/"}); err == nil {
+		t.Error("an invalid regex should be rejected")
+	}
+}
+
+func TestIsInScope(t *testing.T) {
+	tests := []struct {
+		base string
+		url  string
+		want bool
+	}{
+		{"http://localhost:3000", "http://localhost:3000/", true},
+		{"http://localhost:3000", "http://localhost:3000/any/page", true},
+		{"http://localhost:3000", "http://LOCALHOST:3000/page", true},
+		{"http://localhost:3000", "http://localhost:3001/page", false},
+		{"http://localhost:3000", "https://cdn.example.com/page", false},
+		{"http://localhost:3000/docs", "http://localhost:3000/docs", true},
+		{"http://localhost:3000/docs", "http://localhost:3000/docs/install", true},
+		{"http://localhost:3000/docs/", "http://localhost:3000/docs/install", true},
+		{"http://localhost:3000/docs", "http://localhost:3000/docs-old", false},
+		{"http://localhost:3000/docs", "http://localhost:3000/", false},
+		{"http://localhost:3000/docs", "http://localhost:3000/blog/docs", false},
+		{"http://localhost:3000/docs/index.html", "http://localhost:3000/docs/index.html/x", true},
+	}
+
+	for _, tt := range tests {
+		c := NewCrawler(context.Background(), tt.base, 1, 0, 0, nil)
+		if got := c.isInScope(tt.url); got != tt.want {
+			t.Errorf("base %s: isInScope(%q) = %v, want %v", tt.base, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSkipReason(t *testing.T) {
+	c := NewCrawler(context.Background(), "http://localhost:3000", 1, 0, 0, []string{"/blog/tag/**"})
+	c.IncludePatterns = []string{"/blog/**"}
+	if err := c.compileFilters(); err != nil {
+		t.Fatal(err)
+	}
+	c.robots = robots.Parse(strings.NewReader("User-agent: *\nDisallow: /blog/drafts\n"))
+
+	tests := []struct {
+		url      string
+		explicit bool
+		want     string
+	}{
+		{"http://localhost:3000/blog/post", false, ""},
+		{"http://localhost:3000/about", false, SkipOutOfScope},
+		{"http://localhost:3000/about", true, ""}, // The seed and listed URLs ignore includes
+		{"http://localhost:3000/blog/tag/go", false, SkipIgnored},
+		{"http://localhost:3000/blog/tag/go", true, SkipIgnored},
+		{"http://localhost:3000/blog/drafts/next", false, SkipRobots},
+		{"http://localhost:3000/blog/drafts/next", true, SkipRobots},
+	}
+
+	for _, tt := range tests {
+		if got := c.skipReason(tt.url, tt.explicit); got != tt.want {
+			t.Errorf("skipReason(%q, %v) = %q, want %q", tt.url, tt.explicit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxScreenshotSlug caps the readable part of screenshot file names
+const maxScreenshotSlug = 60
+
+// Screenshotter is implemented by sessions that can capture the page they last fetched
+type Screenshotter interface {
+	// Screenshot saves a PNG of the current page; fullPage captures beyond the viewport
+	Screenshot(path string, fullPage bool) error
+}
+
+// PageScreenshots are the PNG captures of a rendered page
+type PageScreenshots struct {
+	FullPage  string // Path of the whole page capture
+	AboveFold string // Path of the capture of the first viewport
+	Error     string // Why the page could not be captured
+}
+
+// captureScreenshots saves the full-page and above-the-fold captures of the
+// page the session just fetched into ScreenshotDir
+func (c *Crawler) captureScreenshots(session Session, pageURL string) *PageScreenshots {
+	shooter, ok := session.(Screenshotter)
+	if !ok {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.ScreenshotDir, 0755); err != nil {
+		return &PageScreenshots{Error: fmt.Sprintf("could not create screenshot directory: %v", err)}
+	}
+
+	name := screenshotName(pageURL)
+	shots := &PageScreenshots{
+		FullPage:  filepath.Join(c.ScreenshotDir, name+"-full.png"),
+		AboveFold: filepath.Join(c.ScreenshotDir, name+"-fold.png"),
+	}
+	if err := shooter.Screenshot(shots.FullPage, true); err != nil {
+		return &PageScreenshots{Error: err.Error()}
+	}
+	if err := shooter.Screenshot(shots.AboveFold, false); err != nil {
+		shots.AboveFold = ""
+		shots.Error = err.Error()
+	}
+	return shots
+}
+
+// screenshotName derives a file name from the page path, with a hash of the
+// full URL so pages differing only in query string do not collide
+func screenshotName(pageURL string) string {
+	slug := "index"
+	if parsed, err := url.Parse(pageURL); err == nil {
+		var b strings.Builder
+		for _, r := range strings.ToLower(strings.Trim(parsed.Path, "/")) {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+				b.WriteRune(r)
+			case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+				b.WriteByte('-')
+			}
+		}
+		if s := strings.Trim(b.String(), "-"); s != "" {
+			slug = s
+		}
+	}
+	if len(slug) > maxScreenshotSlug {
+		slug = strings.TrimRight(slug[:maxScreenshotSlug], "-")
+	}
+
+	sum := sha1.Sum([]byte(pageURL))
+	return slug + "-" + hex.EncodeToString(sum[:4])
+}
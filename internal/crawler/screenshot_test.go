@@ -0,0 +1,134 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestScreenshotName(t *testing.T) {
+	tests := []struct {
+		url      string
+		wantSlug string
+	}{
+		{"http://localhost:3000/", "index"},
+		{"http://localhost:3000", "index"},
+		{"http://localhost:3000/about", "about"},
+		{"http://localhost:3000/Blog/My_First-Post/", "blog-my-first-post"},
+		{"http://localhost:3000/docs/v2.1/index.html", "docs-v2-1-index-html"},
+		{"http://localhost:3000/caf%C3%A9/--menu--", "caf-menu"}, // Non-ASCII runs collapse into one dash
+		{"http://localhost:3000/%E6%97%A5%E6%9C%AC", "index"},
+		{"http://localhost:3000/" + strings.Repeat("section/", 20), strings.TrimSuffix(strings.Repeat("section-", 8)[:maxScreenshotSlug], "-")},
+	}
+
+	valid := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*-[0-9a-f]{8}$`)
+	for _, tt := range tests {
+		name := screenshotName(tt.url)
+		if !strings.HasPrefix(name, tt.wantSlug+"-") {
+			t.Errorf("screenshotName(%q) = %q, want slug %q", tt.url, name, tt.wantSlug)
+		}
+		if !valid.MatchString(name) || len(name) != len(tt.wantSlug)+9 {
+			t.Errorf("screenshotName(%q) = %q, want a lowercase slug and an 8 character hash", tt.url, name)
+		}
+	}
+
+	// Pages differing only in query string get their own files
+	a := screenshotName("http://localhost:3000/search?q=a")
+	b := screenshotName("http://localhost:3000/search?q=b")
+	if a == b || !strings.HasPrefix(a, "search-") || !strings.HasPrefix(b, "search-") {
+		t.Errorf("screenshotName() = %q and %q, want distinct names for distinct URLs", a, b)
+	}
+	if screenshotName("http://localhost:3000/search?q=a") != a {
+		t.Error("screenshotName() is not stable")
+	}
+}
+
+// shooterSession is a session that writes fake screenshots
+type shooterSession struct {
+	failFullPage  bool
+	failAboveFold bool
+}
+
+func (s *shooterSession) Fetch(ctx context.Context, pageURL string) (*PageResult, error) {
+	return &PageResult{URL: pageURL}, nil
+}
+
+func (s *shooterSession) Close() {}
+
+func (s *shooterSession) Screenshot(path string, fullPage bool) error {
+	if (fullPage && s.failFullPage) || (!fullPage && s.failAboveFold) {
+		return errors.New("capture failed")
+	}
+	return os.WriteFile(path, []byte("png"), 0644)
+}
+
+func TestCaptureScreenshots(t *testing.T) {
+	const pageURL = "http://localhost:3000/pricing"
+	name := screenshotName(pageURL)
+
+	tests := []struct {
+		name    string
+		session Session
+		want    *PageScreenshots // Paths relative to the screenshot directory
+	}{
+		{
+			name:    "both captures",
+			session: &shooterSession{},
+			want:    &PageScreenshots{FullPage: name + "-full.png", AboveFold: name + "-fold.png"},
+		},
+		{
+			name:    "full page fails",
+			session: &shooterSession{failFullPage: true},
+			want:    &PageScreenshots{Error: "capture failed"},
+		},
+		{
+			name:    "above the fold fails",
+			session: &shooterSession{failAboveFold: true},
+			want:    &PageScreenshots{FullPage: name + "-full.png", Error: "capture failed"},
+		},
+		{
+			name:    "session cannot capture",
+			session: &freshSession{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCrawler(context.Background(), "http://localhost:3000", 1, 0, 0, nil)
+			c.ScreenshotDir = filepath.Join(t.TempDir(), "screenshots") // Created on demand
+
+			got := c.captureScreenshots(tt.session, pageURL)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("captureScreenshots() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("captureScreenshots() = nil")
+			}
+
+			for _, path := range []struct{ got, want string }{{got.FullPage, tt.want.FullPage}, {got.AboveFold, tt.want.AboveFold}} {
+				if path.want == "" {
+					if path.got != "" {
+						t.Errorf("screenshot path = %q, want none", path.got)
+					}
+					continue
+				}
+				if want := filepath.Join(c.ScreenshotDir, path.want); path.got != want {
+					t.Errorf("screenshot path = %q, want %q", path.got, want)
+				}
+				if _, err := os.Stat(path.got); err != nil {
+					t.Errorf("screenshot was not written: %v", err)
+				}
+			}
+			if got.Error != tt.want.Error {
+				t.Errorf("Error = %q, want %q", got.Error, tt.want.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package crawler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Discovery sources recorded on each crawled page
+const (
+	SourceSeed    = "seed"
+	SourceLink    = "link"
+	SourceSitemap = "sitemap"
+	SourceList    = "list" // Given explicitly in list mode
+)
+
+const (
+	maxSitemapDocuments = 50               // Upper bound on sitemap files fetched per crawl
+	maxSitemapBytes     = 50 * 1024 * 1024 // Sitemap protocol limit for uncompressed files
+)
+
+// SitemapCoverage compares the URLs listed in sitemaps with the pages linked on the site
+type SitemapCoverage struct {
+	SitemapURLs  int      // Number of in-scope URLs listed in sitemaps
+	Unlinked     []string // Listed in a sitemap but never linked from a crawled page
+	NotInSitemap []string // Linked and crawled successfully, but missing from sitemaps
+}
+
+// sitemapDocument covers both <urlset> and <sitemapindex> documents
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// fetchSitemaps collects every in-scope page URL listed in the site's sitemaps.
+// It starts from robots.txt Sitemap lines plus /sitemap.xml and follows sitemap indexes.
+func (c *Crawler) fetchSitemaps() []string {
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return nil
+	}
+
+	var pending []string
+	if rules := c.Robots(); rules != nil {
+		pending = append(pending, rules.Sitemaps...)
+	}
+	pending = append(pending, fmt.Sprintf("%s://%s/sitemap.xml", baseURL.Scheme, baseURL.Host))
+
+	// Sites served under a base path often publish their own sitemap there
+	if basePath := strings.TrimSuffix(baseURL.Path, "/"); basePath != "" {
+		pending = append(pending, fmt.Sprintf("%s://%s%s/sitemap.xml", baseURL.Scheme, baseURL.Host, basePath))
+	}
+
+	client, err := newHTTPClient(c.fetcherOptions(), 10*time.Second)
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	fetched := 0
+
+	for len(pending) > 0 && fetched < maxSitemapDocuments && c.ctx.Err() == nil {
+		sitemapURL := pending[0]
+		pending = pending[1:]
+
+		if seen[sitemapURL] {
+			continue
+		}
+		seen[sitemapURL] = true
+		fetched++
+
+		doc, err := fetchSitemapDocument(c.ctx, client, sitemapURL)
+		if err != nil {
+			continue // Missing or invalid sitemap - skip it
+		}
+
+		// Sitemap indexes point to further sitemaps
+		for _, s := range doc.Sitemaps {
+			if loc := strings.TrimSpace(s.Loc); loc != "" {
+				pending = append(pending, loc)
+			}
+		}
+
+		for _, u := range doc.URLs {
+			loc := strings.TrimSpace(u.Loc)
+			if loc == "" {
+				continue
+			}
+
+			normalized := c.normalizeURL(loc)
+			if !c.isInScope(normalized) {
+				continue
+			}
+
+			c.mu.Lock()
+			c.sitemapURLs[normalized] = true
+			c.mu.Unlock()
+		}
+	}
+
+	c.mu.RLock()
+	urls := make([]string, 0, len(c.sitemapURLs))
+	for u := range c.sitemapURLs {
+		urls = append(urls, u)
+	}
+	c.mu.RUnlock()
+
+	sort.Strings(urls)
+	return urls
+}
+
+// fetchSitemapDocument downloads and decodes a single sitemap, transparently handling gzip
+func fetchSitemapDocument(ctx context.Context, client *http.Client, sitemapURL string) (*sitemapDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	// Detect gzip by its magic bytes, as .gz sitemaps are often served
+	// without a matching Content-Encoding header
+	reader := bufio.NewReader(io.LimitReader(resp.Body, maxSitemapBytes))
+	var body io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip sitemap: %w", err)
+		}
+		defer gz.Close()
+		body = io.LimitReader(gz, maxSitemapBytes)
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid sitemap XML: %w", err)
+	}
+
+	return &doc, nil
+}
+
+// GetSitemapCoverage reports sitemap URLs that were never linked and linked pages missing from sitemaps
+func (c *Crawler) GetSitemapCoverage() SitemapCoverage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	coverage := SitemapCoverage{
+		SitemapURLs: len(c.sitemapURLs),
+	}
+
+	// Without a sitemap there is nothing to compare against
+	if len(c.sitemapURLs) == 0 {
+		return coverage
+	}
+
+	normalizedBase := c.normalizeURL(c.BaseURL)
+	for u := range c.sitemapURLs {
+		if !c.linkedURLs[u] && u != normalizedBase {
+			coverage.Unlinked = append(coverage.Unlinked, u)
+		}
+	}
+
+	for u, page := range c.crawled {
+		if page.StatusCode == 200 && c.linkedURLs[u] && !c.sitemapURLs[u] {
+			coverage.NotInSitemap = append(coverage.NotInSitemap, u)
+		}
+	}
+
+	sort.Strings(coverage.Unlinked)
+	sort.Strings(coverage.NotInSitemap)
+	return coverage
+}
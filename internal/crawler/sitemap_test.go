@@ -0,0 +1,279 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// urlset renders a sitemap listing locs
+func urlset(locs ...string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	for _, loc := range locs {
+		fmt.Fprintf(&b, "<url><loc>%s</loc></url>", loc)
+	}
+	b.WriteString("</urlset>")
+	return b.String()
+}
+
+// sitemapIndex renders a sitemap index pointing to locs
+func sitemapIndex(locs ...string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	for _, loc := range locs {
+		fmt.Fprintf(&b, "<sitemap><loc>%s</loc></sitemap>", loc)
+	}
+	b.WriteString("</sitemapindex>")
+	return b.String()
+}
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// sitemapSite serves the given documents by path and records which were fetched
+type sitemapSite struct {
+	*httptest.Server
+	mu      sync.Mutex
+	fetched []string
+}
+
+func newSitemapSite(t *testing.T, docs map[string]func(base string) []byte) *sitemapSite {
+	site := &sitemapSite{}
+	site.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		site.mu.Lock()
+		site.fetched = append(site.fetched, r.URL.Path)
+		site.mu.Unlock()
+
+		doc, ok := docs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(doc(site.URL))
+	}))
+	t.Cleanup(site.Close)
+	return site
+}
+
+func (s *sitemapSite) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, fetched := range s.fetched {
+		if fetched == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestFetchSitemaps(t *testing.T) {
+	site := newSitemapSite(t, map[string]func(string) []byte{
+		"/robots.txt": func(base string) []byte {
+			return []byte("User-agent: *\nAllow: /\nSitemap: " + base + "/sitemaps/index.xml\n")
+		},
+		"/sitemaps/index.xml": func(base string) []byte {
+			return []byte(sitemapIndex(
+				base+"/sitemaps/pages.xml.gz",
+				base+"/sitemaps/posts.xml",
+				base+"/sitemaps/index.xml", // Listed again; fetched once
+				base+"/sitemaps/missing.xml",
+				base+"/sitemaps/broken.xml",
+			))
+		},
+		"/sitemaps/pages.xml.gz": func(base string) []byte {
+			// Served without Content-Encoding, as .gz sitemaps usually are
+			return gzipped(t, urlset(base+"/", base+"/pricing", base+"/about"))
+		},
+		"/sitemaps/posts.xml": func(base string) []byte {
+			return []byte(urlset(
+				"  "+base+"/blog/first  ",
+				base+"/blog/second?utm_source=sitemap",
+				"https://cdn.example.com/blog/third", // Off-host
+				"",
+			))
+		},
+		"/sitemaps/broken.xml": func(base string) []byte { return []byte("<urlset><url><loc>") },
+		"/sitemap.xml": func(base string) []byte {
+			return []byte(urlset(base+"/pricing", base+"/contact"))
+		},
+	})
+
+	c := NewCrawler(context.Background(), site.URL, 1, 0, 0, nil)
+	c.fetchRobotsTxt()
+	got := c.fetchSitemaps()
+
+	want := []string{
+		site.URL + "/", site.URL + "/about", site.URL + "/blog/first", site.URL + "/blog/second",
+		site.URL + "/contact", site.URL + "/pricing",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("fetchSitemaps() = %v, want %v", got, want)
+	}
+	for _, path := range []string{"/sitemaps/index.xml", "/sitemaps/pages.xml.gz", "/sitemaps/posts.xml", "/sitemap.xml"} {
+		if n := site.count(path); n != 1 {
+			t.Errorf("%s fetched %d times, want once", path, n)
+		}
+	}
+}
+
+func TestFetchSitemapsBasePath(t *testing.T) {
+	site := newSitemapSite(t, map[string]func(string) []byte{
+		"/sitemap.xml": func(base string) []byte {
+			return []byte(urlset(base+"/", base+"/docs", base+"/docs-old/a", base+"/pricing"))
+		},
+		"/docs/sitemap.xml": func(base string) []byte {
+			return []byte(urlset(base+"/docs/intro", base+"/docs/api/"))
+		},
+	})
+
+	c := NewCrawler(context.Background(), site.URL+"/docs/", 1, 0, 0, nil)
+	got := c.fetchSitemaps()
+
+	// Only URLs under /docs are in scope
+	want := []string{site.URL + "/docs", site.URL + "/docs/api", site.URL + "/docs/intro"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("fetchSitemaps() = %v, want %v", got, want)
+	}
+}
+
+func TestFetchSitemapsLimit(t *testing.T) {
+	// Each sitemap points to the next, forever
+	var requests atomic.Int32
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		n := requests.Add(1)
+		fmt.Fprint(w, sitemapIndex(fmt.Sprintf("http://%s/sitemap-%d.xml", r.Host, n)))
+	}))
+	defer site.Close()
+
+	c := NewCrawler(context.Background(), site.URL, 1, 0, 0, nil)
+	c.fetchSitemaps()
+	if got := requests.Load(); got != maxSitemapDocuments {
+		t.Errorf("%d sitemaps fetched, want the limit of %d", got, maxSitemapDocuments)
+	}
+}
+
+func TestFetchSitemapDocument(t *testing.T) {
+	body := urlset("http://localhost:3000/a")
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr string
+	}{
+		{
+			name:    "plain",
+			handler: func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, body) },
+		},
+		{
+			name: "gzip without Content-Encoding",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/x-gzip")
+				w.Write(gzipped(t, body))
+			},
+		},
+		{
+			name: "Content-Encoding gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Write(gzipped(t, body))
+			},
+		},
+		{
+			name: "truncated gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write(gzipped(t, body)[:12])
+			},
+			wantErr: "invalid",
+		},
+		{
+			name:    "not found",
+			handler: http.NotFound,
+			wantErr: "unexpected status 404",
+		},
+		{
+			name:    "HTML page",
+			handler: func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "<html><body>Not a sitemap") },
+			wantErr: "invalid sitemap XML",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			doc, err := fetchSitemapDocument(context.Background(), server.Client(), server.URL+"/sitemap.xml")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("fetchSitemapDocument() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(doc.URLs) != 1 || doc.URLs[0].Loc != "http://localhost:3000/a" {
+				t.Errorf("URLs = %+v, want http://localhost:3000/a", doc.URLs)
+			}
+		})
+	}
+}
+
+func TestGetSitemapCoverage(t *testing.T) {
+	site := newSitemapSite(t, map[string]func(string) []byte{
+		"/": func(string) []byte {
+			return []byte(`<html><body><a href="/linked">Linked</a><a href="/unlisted">Unlisted</a><a href="/gone">Gone</a></body></html>`)
+		},
+		"/linked":   func(string) []byte { return []byte("<html><body>Linked</body></html>") },
+		"/unlisted": func(string) []byte { return []byte("<html><body>Unlisted</body></html>") },
+		"/orphan":   func(string) []byte { return []byte("<html><body>Orphan</body></html>") },
+		"/sitemap.xml": func(base string) []byte {
+			return []byte(urlset(base+"/", base+"/linked", base+"/orphan"))
+		},
+	})
+
+	c := NewCrawler(context.Background(), site.URL, 2, 0, 0, nil)
+	c.Renderer = RendererHTTP
+	go func() {
+		for range c.Pages() {
+		}
+	}()
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	coverage := c.GetSitemapCoverage()
+	if coverage.SitemapURLs != 3 {
+		t.Errorf("SitemapURLs = %d, want 3", coverage.SitemapURLs)
+	}
+	// The home page is the seed; it is never reported as unlinked
+	if want := []string{site.URL + "/orphan"}; strings.Join(coverage.Unlinked, " ") != strings.Join(want, " ") {
+		t.Errorf("Unlinked = %v, want %v", coverage.Unlinked, want)
+	}
+	// /gone is linked but returns 404, so only /unlisted is missing from the sitemap
+	if want := []string{site.URL + "/unlisted"}; strings.Join(coverage.NotInSitemap, " ") != strings.Join(want, " ") {
+		t.Errorf("NotInSitemap = %v, want %v", coverage.NotInSitemap, want)
+	}
+}
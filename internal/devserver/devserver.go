@@ -0,0 +1,110 @@
+// Package devserver starts the site's development server for the duration of an
+// audit and waits until it serves requests.
+package devserver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const (
+	// pollInterval is how often Wait checks whether the server responds
+	pollInterval = 250 * time.Millisecond
+	// stopTimeout is how long Stop lets the server shut down before killing it
+	stopTimeout = 5 * time.Second
+)
+
+// Server is a running development server command
+type Server struct {
+	cmd  *exec.Cmd
+	log  *os.File
+	done chan struct{} // Closed when the command exits
+	err  error         // Exit error, set before done is closed
+}
+
+// Start runs command through the shell with its output appended to logPath.
+// Stop shuts down everything the command spawned, as npm scripts usually run the
+// real server as a child process.
+func Start(command, logPath string) (*Server, error) {
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dev server log: %w", err)
+	}
+	fmt.Fprintf(logFile, "$ %s (started %s)\n", command, time.Now().Format(time.RFC3339))
+
+	cmd := shellCommand(command)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("failed to start %q: %w", command, err)
+	}
+
+	s := &Server{cmd: cmd, log: logFile, done: make(chan struct{})}
+	go func() {
+		s.err = cmd.Wait()
+		close(s.done)
+	}()
+	return s, nil
+}
+
+// Wait polls target until it answers with any HTTP response. It fails if the
+// command exits first, timeout passes or ctx is cancelled.
+func (s *Server) Wait(ctx context.Context, target string, timeout time.Duration) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid wait-for URL %q (expected http:// or https://)", target)
+	}
+
+	client := &http.Client{
+		Timeout: 2 * time.Second,
+		// Dev servers often use self-signed certificates; only liveness matters here
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		if resp, err := client.Do(req); err == nil {
+			resp.Body.Close()
+			return nil
+		}
+
+		select {
+		case <-s.done:
+			if s.err != nil {
+				return fmt.Errorf("dev server exited before %s responded: %w", target, s.err)
+			}
+			return fmt.Errorf("dev server exited before %s responded", target)
+		case <-deadline.C:
+			return fmt.Errorf("%s did not respond within %s", target, timeout)
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop shuts down the command and its child processes, and closes the log
+func (s *Server) Stop() error {
+	defer s.log.Close()
+	return s.stopProcessTree()
+}
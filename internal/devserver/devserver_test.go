@@ -0,0 +1,153 @@
+package devserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runningServer is a Server whose command has not exited
+func runningServer() *Server {
+	return &Server{done: make(chan struct{})}
+}
+
+// exitedServer is a Server whose command exited with err
+func exitedServer(err error) *Server {
+	s := &Server{done: make(chan struct{}), err: err}
+	close(s.done)
+	return s
+}
+
+// freeAddress returns a local address nothing listens on
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	return addr
+}
+
+func TestWait(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/login":
+			http.Redirect(w, r, "http://127.0.0.1:1/unreachable", http.StatusFound)
+		}
+	}))
+	defer up.Close()
+	upTLS := httptest.NewTLSServer(http.NotFoundHandler())
+	defer upTLS.Close()
+	down := "http://" + freeAddress(t) + "/"
+
+	tests := []struct {
+		name    string
+		server  *Server
+		target  string
+		timeout time.Duration
+		wantErr string
+	}{
+		{name: "responds", server: runningServer(), target: up.URL, timeout: time.Second},
+		{name: "any status counts", server: runningServer(), target: up.URL + "/error", timeout: time.Second},
+		{name: "redirects are not followed", server: runningServer(), target: up.URL + "/login", timeout: time.Second},
+		{name: "self-signed certificate", server: runningServer(), target: upTLS.URL, timeout: time.Second},
+		{
+			name:    "timeout",
+			server:  runningServer(),
+			target:  down,
+			timeout: 300 * time.Millisecond,
+			wantErr: "did not respond within 300ms",
+		},
+		{
+			name:    "command exited",
+			server:  exitedServer(errors.New("exit status 1")),
+			target:  down,
+			timeout: time.Minute,
+			wantErr: "dev server exited before " + down + " responded: exit status 1",
+		},
+		{
+			name:    "command exited cleanly",
+			server:  exitedServer(nil),
+			target:  down,
+			timeout: time.Minute,
+			wantErr: "dev server exited before",
+		},
+		{name: "no scheme", server: runningServer(), target: "localhost:3000", wantErr: "invalid wait-for URL"},
+		{name: "other scheme", server: runningServer(), target: "ftp://localhost:3000", wantErr: "invalid wait-for URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.server.Wait(context.Background(), tt.target, tt.timeout)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Wait() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Wait() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForSlowStart(t *testing.T) {
+	addr := freeAddress(t)
+
+	// The server starts listening after a few polls
+	started := make(chan *http.Server, 1)
+	go func() {
+		time.Sleep(3 * pollInterval)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			started <- nil
+			return
+		}
+		server := &http.Server{Handler: http.NotFoundHandler()}
+		started <- server
+		server.Serve(listener)
+	}()
+
+	start := time.Now()
+	err := runningServer().Wait(context.Background(), "http://"+addr+"/", 10*time.Second)
+	server := <-started
+	if server == nil {
+		t.Skipf("could not listen on %s again", addr)
+	}
+	defer server.Close()
+
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 2*pollInterval {
+		t.Errorf("Wait() returned after %s, before the server started", elapsed)
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	err := runningServer().Wait(ctx, "http://"+freeAddress(t)+"/", time.Minute)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want the context error", err)
+	}
+}
+
+func TestStartLogError(t *testing.T) {
+	_, err := Start("exit 0", filepath.Join(t.TempDir(), "missing", "devserver.log"))
+	if err == nil || !strings.Contains(err.Error(), "failed to create dev server log") {
+		t.Errorf("Start() error = %v, want a log error", err)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build unix
+
+package devserver
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// shellCommand runs command through sh in its own process group, so the whole
+// group can be signalled
+func shellCommand(command string) *exec.Cmd {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+// stopProcessTree sends SIGTERM to the command's process group and kills
+// whatever is left of it after stopTimeout
+func (s *Server) stopProcessTree() error {
+	pgid := s.cmd.Process.Pid
+	if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("failed to stop dev server: %w", err)
+	}
+
+	// The shell may exit before the server it started has shut down, so wait
+	// for the whole group to be gone
+	deadline := time.Now().Add(stopTimeout)
+	for time.Now().Before(deadline) {
+		if err := syscall.Kill(-pgid, 0); errors.Is(err, syscall.ESRCH) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	syscall.Kill(-pgid, syscall.SIGKILL)
+	<-s.done
+	return nil
+}
@@ -0,0 +1,123 @@
+//go:build unix
+
+package devserver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// alive reports whether a process exists
+func alive(pid int) bool {
+	return !errors.Is(syscall.Kill(pid, 0), syscall.ESRCH)
+}
+
+// readPID waits for the command to write a process ID to path
+func readPID(t *testing.T, path string) int {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil && strings.HasSuffix(string(data), "\n") {
+			pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return pid
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("the command did not write %s", path)
+	return 0
+}
+
+func TestStopProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "child.pid")
+	logPath := filepath.Join(dir, "devserver.log")
+
+	// Like npm run dev: the shell starts the real server as a child and waits
+	server, err := Start("echo starting; sleep 60 & echo $! > "+pidFile+"; wait", logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := readPID(t, pidFile)
+	if !alive(child) {
+		t.Fatal("child process is not running")
+	}
+
+	start := time.Now()
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= stopTimeout {
+		t.Errorf("Stop() took %s; SIGTERM should have been enough", elapsed)
+	}
+
+	// sleep is reaped by init once its shell is gone, so give it a moment
+	deadline := time.Now().Add(2 * time.Second)
+	for alive(child) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if alive(child) {
+		syscall.Kill(child, syscall.SIGKILL)
+		t.Error("the child process outlived Stop()")
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(log), "$ echo starting;") || !strings.Contains(string(log), "\nstarting\n") {
+		t.Errorf("log = %q, want the command and its output", log)
+	}
+}
+
+func TestStopIgnoringSIGTERM(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the stop timeout")
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	server, err := Start("trap '' TERM; sleep 60 & echo $! > "+pidFile+"; wait", filepath.Join(t.TempDir(), "devserver.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := readPID(t, pidFile)
+
+	start := time.Now()
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < stopTimeout {
+		t.Errorf("Stop() returned after %s, before the stop timeout", elapsed)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for alive(child) && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if alive(child) {
+		syscall.Kill(child, syscall.SIGKILL)
+		t.Error("the child process survived SIGKILL")
+	}
+}
+
+func TestWaitForExitedCommand(t *testing.T) {
+	server, err := Start("exit 3", filepath.Join(t.TempDir(), "devserver.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	err = server.Wait(context.Background(), "http://127.0.0.1:1/", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Wait() error = %v, want the exit status", err)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build windows
+
+package devserver
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// shellCommand runs command through cmd.exe
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
+
+// stopProcessTree kills the command and its child processes with taskkill /T.
+// Console programs cannot be asked to exit gracefully from another console.
+func (s *Server) stopProcessTree() error {
+	pid := strconv.Itoa(s.cmd.Process.Pid)
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", pid).Run(); err != nil {
+		select {
+		case <-s.done:
+			return nil // Already exited
+		default:
+		}
+		return fmt.Errorf("failed to stop dev server: %w", err)
+	}
+
+	select {
+	case <-s.done:
+	case <-time.After(stopTimeout):
+		return fmt.Errorf("dev server did not exit within %s", stopTimeout)
+	}
+	return nil
+}
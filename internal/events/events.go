@@ -0,0 +1,35 @@
+package events
+
+// Type identifies what happened during an audit
+type Type string
+
+const (
+	PageDiscovered Type = "page_discovered" // A URL was added to the crawl frontier
+	PageFetched    Type = "page_fetched"    // A page was fetched (Error is set if the request failed)
+	PageAnalyzed   Type = "page_analyzed"   // A page was analyzed and stored
+	PageFailed     Type = "page_failed"     // A page could not be analyzed (HTTP error, redirect loop, bad HTML)
+	AuditCompleted Type = "audit_completed" // The audit was finalized
+)
+
+// Event is a progress update published by the crawler and the audit processor
+type Event struct {
+	Type       Type
+	AuditID    string   // Set on processor events
+	URL        string   // Page the event is about; empty for AuditCompleted
+	Depth      int      // Crawl depth of the page
+	StatusCode int      // HTTP status of a fetched page
+	Score      *float64 // SEO score of an analyzed page
+	Error      string   // Why a fetch or analysis failed
+	Partial    bool     // AuditCompleted: the audit was interrupted and holds partial results
+}
+
+// Handler receives events. It is called synchronously from crawler and processor
+// goroutines, so it must be safe for concurrent use and return quickly.
+type Handler func(Event)
+
+// Publish sends an event to the handler, if there is one
+func (h Handler) Publish(event Event) {
+	if h != nil {
+		h(event)
+	}
+}
@@ -120,6 +120,15 @@ func FormatAIPromptFromMultiplePages(pages []PageDetailsResponse) string {
 			prompt.WriteString("This may indicate an issue with the API response format.\n\n")
 		}
 
+		// Runtime errors give the exact assets and exceptions to fix
+		if len(page.RuntimeErrors) > 0 {
+			prompt.WriteString("### JavaScript Errors and Broken Assets\n\n")
+			for _, line := range page.RuntimeErrors {
+				prompt.WriteString(fmt.Sprintf("- %s\n", line))
+			}
+			prompt.WriteString("\n")
+		}
+
 		prompt.WriteString("---\n\n")
 	}
 
@@ -27,5 +27,6 @@ type PageDetailsResponse struct {
 		Checks             []SEOCheckResponse `json:"checks"`
 		AnalyzedAt         string             `json:"analyzed_at,omitempty"`
 		IssuesCount        int                `json:"issues_count"`
+		RuntimeErrors      []string           `json:"runtime_errors,omitempty"`
 	} `json:"page"`
 }
@@ -0,0 +1,422 @@
+// Package robots parses robots.txt files and decides whether a crawler may
+// fetch a URL, following RFC 9309 (https://www.rfc-editor.org/rfc/rfc9309).
+package robots
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSize is how much of a robots.txt file is parsed; RFC 9309 requires at least 500 KiB
+const MaxSize = 500 * 1024
+
+// access describes what a robots.txt allows when it could not be parsed
+type access int
+
+const (
+	accessRules       access = iota // Rules from a parsed robots.txt apply
+	accessAllowAll                  // robots.txt is unavailable (4xx): no restrictions
+	accessDisallowAll               // robots.txt is unreachable (5xx, network error): nothing may be crawled
+)
+
+// Robots is a parsed robots.txt file
+type Robots struct {
+	URL      string   // Where robots.txt was fetched from; empty when parsed from a reader
+	Status   int      // HTTP status of the robots.txt response; 0 when it was not fetched
+	Groups   []Group  // User-agent groups in file order
+	Sitemaps []string // Sitemap lines, which apply to every crawler
+	access   access
+	reason   string // Why access is not accessRules
+}
+
+// Group is a set of user-agent lines and the rules that follow them
+type Group struct {
+	Agents     []string      // Lowercased product tokens, or "*"
+	Rules      []Rule        // Allow and Disallow rules in file order
+	CrawlDelay time.Duration // Non-standard Crawl-delay, 0 when absent
+	Line       int           // Line of the group's first user-agent
+}
+
+// Rule is an Allow or Disallow line
+type Rule struct {
+	Allow   bool
+	Pattern string // Path pattern with percent-encoding normalized; may use * and a trailing $
+	Line    int
+}
+
+// Verdict explains whether a URL may be crawled
+type Verdict struct {
+	Allowed bool
+	Path    string   // Path and query that were matched
+	Agents  []string // User-agents of the groups that applied; empty when no group matched
+	Rule    *Rule    // Most specific matching rule; nil when no rule matched
+	Reason  string   // Human-readable explanation
+}
+
+// String formats the rule as it appears in robots.txt
+func (r Rule) String() string {
+	if r.Allow {
+		return "Allow: " + r.Pattern
+	}
+	return "Disallow: " + r.Pattern
+}
+
+// Parse reads a robots.txt file. Lines it does not understand are skipped, and
+// anything beyond MaxSize is ignored.
+func Parse(r io.Reader) *Robots {
+	robots := &Robots{}
+
+	scanner := bufio.NewScanner(io.LimitReader(r, MaxSize))
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxSize)
+
+	var current *Group
+	inRules := false // Whether the current group has seen a line other than user-agent
+
+	lineNum := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		// Comments can start anywhere on a line
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		switch field {
+		case "user-agent":
+			// Consecutive user-agent lines share the rules that follow them
+			if current == nil || inRules {
+				robots.Groups = append(robots.Groups, Group{Line: lineNum})
+				current = &robots.Groups[len(robots.Groups)-1]
+				inRules = false
+			}
+			if agent := ProductToken(value); agent != "" {
+				current.Agents = append(current.Agents, agent)
+			}
+
+		case "allow", "disallow":
+			if current == nil {
+				continue // Rules before any user-agent belong to no group
+			}
+			inRules = true
+			// An empty pattern matches nothing
+			if value != "" {
+				current.Rules = append(current.Rules, Rule{
+					Allow:   field == "allow",
+					Pattern: normalizePath(value),
+					Line:    lineNum,
+				})
+			}
+
+		case "crawl-delay":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.CrawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+
+		case "sitemap":
+			if value != "" {
+				robots.Sitemaps = append(robots.Sitemaps, value)
+			}
+		}
+	}
+
+	return robots
+}
+
+// maxRedirects is how many redirects Fetch follows before it gives up on
+// robots.txt; RFC 9309 asks for at least five
+const maxRedirects = 5
+
+// Fetch downloads and parses robots.txt for the site of siteURL. As RFC 9309
+// requires, a 4xx response or a redirect that does not lead to a file within
+// maxRedirects hops allows everything, while a 5xx response or a network error
+// disallows everything; in that case the error is returned with a Robots that
+// reflects it, and Unreachable reports true.
+func Fetch(ctx context.Context, client *http.Client, siteURL string) (*Robots, error) {
+	parsed, err := url.Parse(siteURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid URL: %s", siteURL)
+	}
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", parsed.Scheme, parsed.Host)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stop at the last redirect instead of failing, so it is handled below
+	limited := *client
+	limited.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+
+	resp, err := limited.Do(req)
+	if err != nil {
+		return &Robots{URL: robotsURL, access: accessDisallowAll, reason: "robots.txt is unreachable"}, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		robots := Parse(resp.Body)
+		robots.URL = robotsURL
+		robots.Status = resp.StatusCode
+		return robots, nil
+
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return &Robots{
+			URL:    robotsURL,
+			Status: resp.StatusCode,
+			access: accessAllowAll,
+			reason: fmt.Sprintf("robots.txt returned %d, so there are no restrictions", resp.StatusCode),
+		}, nil
+
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return &Robots{
+			URL:    robotsURL,
+			Status: resp.StatusCode,
+			access: accessAllowAll,
+			reason: fmt.Sprintf("robots.txt returned %d without leading to a file, so there are no restrictions", resp.StatusCode),
+		}, nil
+
+	default:
+		return &Robots{
+			URL:    robotsURL,
+			Status: resp.StatusCode,
+			access: accessDisallowAll,
+			reason: fmt.Sprintf("robots.txt returned %d, so crawling is not allowed", resp.StatusCode),
+		}, fmt.Errorf("robots.txt returned status %d", resp.StatusCode)
+	}
+}
+
+// Unreachable reports whether robots.txt could not be fetched (a 5xx response or
+// a network error), which disallows everything
+func (r *Robots) Unreachable() bool {
+	return r.access == accessDisallowAll
+}
+
+// ProductToken extracts the product token a robots.txt group is matched on,
+// e.g. "googlebot" from "Googlebot/2.1". It returns "*" for the wildcard.
+func ProductToken(userAgent string) string {
+	userAgent = strings.TrimSpace(userAgent)
+	if strings.HasPrefix(userAgent, "*") {
+		return "*"
+	}
+
+	end := strings.IndexFunc(userAgent, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '_')
+	})
+	if end >= 0 {
+		userAgent = userAgent[:end]
+	}
+	return strings.ToLower(userAgent)
+}
+
+// Allowed reports whether userAgent may crawl target, a URL or a path
+func (r *Robots) Allowed(userAgent, target string) bool {
+	return r.Test(userAgent, target).Allowed
+}
+
+// Test decides whether userAgent may crawl target, a URL or a path, and explains why
+func (r *Robots) Test(userAgent, target string) Verdict {
+	path := requestPath(target)
+	verdict := Verdict{Allowed: true, Path: path}
+
+	switch r.access {
+	case accessAllowAll:
+		verdict.Reason = r.reason
+		return verdict
+	case accessDisallowAll:
+		verdict.Allowed = false
+		verdict.Reason = r.reason
+		return verdict
+	}
+
+	// robots.txt itself is always allowed
+	if path == "/robots.txt" {
+		verdict.Reason = "/robots.txt is always allowed"
+		return verdict
+	}
+
+	group, ok := r.group(userAgent)
+	if !ok {
+		verdict.Reason = fmt.Sprintf("no group matches %q and there is no * group", ProductToken(userAgent))
+		return verdict
+	}
+	verdict.Agents = group.Agents
+
+	// The longest matching pattern wins; on a tie, Allow wins
+	var match *Rule
+	for i := range group.Rules {
+		rule := &group.Rules[i]
+		if !matches(rule.Pattern, path) {
+			continue
+		}
+		if match == nil || len(rule.Pattern) > len(match.Pattern) ||
+			(len(rule.Pattern) == len(match.Pattern) && rule.Allow && !match.Allow) {
+			match = rule
+		}
+	}
+
+	if match == nil {
+		verdict.Reason = "no rule matches the path"
+		return verdict
+	}
+
+	verdict.Rule = match
+	verdict.Allowed = match.Allow
+	verdict.Reason = fmt.Sprintf("%q is the most specific matching rule (line %d)", match.String(), match.Line)
+	return verdict
+}
+
+// CrawlDelay returns the Crawl-delay of the group that applies to userAgent
+func (r *Robots) CrawlDelay(userAgent string) time.Duration {
+	group, _ := r.group(userAgent)
+	return group.CrawlDelay
+}
+
+// group merges every group naming the product token of userAgent, falling back
+// to the * groups when none does
+func (r *Robots) group(userAgent string) (Group, bool) {
+	token := ProductToken(userAgent)
+
+	merged := Group{}
+	found := false
+	for _, name := range []string{token, "*"} {
+		for _, group := range r.Groups {
+			if !containsAgent(group.Agents, name) {
+				continue
+			}
+			if !found {
+				merged.Line = group.Line
+			}
+			found = true
+			merged.Rules = append(merged.Rules, group.Rules...)
+			if merged.CrawlDelay == 0 {
+				merged.CrawlDelay = group.CrawlDelay
+			}
+		}
+		if found {
+			merged.Agents = []string{name}
+			return merged, true
+		}
+	}
+
+	return merged, false
+}
+
+func containsAgent(agents []string, name string) bool {
+	for _, agent := range agents {
+		if agent == name {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether path matches a robots.txt pattern. * matches any
+// sequence of characters and a trailing $ anchors the pattern at the end of the
+// path; otherwise the pattern only has to match a prefix of the path.
+func matches(pattern, path string) bool {
+	// positions holds every offset in path where the pattern read so far can end
+	positions := []int{0}
+
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+
+		if ch == '$' && i == len(pattern)-1 {
+			return positions[len(positions)-1] == len(path)
+		}
+
+		if ch == '*' {
+			// Offsets are sorted, so the wildcard can end anywhere from the first one on
+			start := positions[0]
+			positions = make([]int, 0, len(path)-start+1)
+			for p := start; p <= len(path); p++ {
+				positions = append(positions, p)
+			}
+			continue
+		}
+
+		next := positions[:0]
+		for _, p := range positions {
+			if p < len(path) && path[p] == ch {
+				next = append(next, p+1)
+			}
+		}
+		if len(next) == 0 {
+			return false
+		}
+		positions = next
+	}
+
+	return true
+}
+
+// requestPath returns the path and query of target, a URL or a path, with its
+// percent-encoding normalized for matching
+func requestPath(target string) string {
+	path := target
+	if parsed, err := url.Parse(target); err == nil {
+		path = parsed.EscapedPath()
+		if parsed.RawQuery != "" {
+			path += "?" + parsed.RawQuery
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	return normalizePath(path)
+}
+
+// normalizePath percent-encodes bytes outside printable ASCII and uppercases
+// existing escapes, so patterns and paths compare octet by octet
+func normalizePath(path string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		ch := path[i]
+		switch {
+		case ch == '%' && i+2 < len(path) && isHex(path[i+1]) && isHex(path[i+2]):
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(path[i+1 : i+3]))
+			i += 2
+		case ch <= ' ' || ch >= 0x7f:
+			b.WriteByte('%')
+			b.WriteByte(hex[ch>>4])
+			b.WriteByte(hex[ch&0x0f])
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+func isHex(ch byte) bool {
+	return ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'f' || ch >= 'A' && ch <= 'F'
+}
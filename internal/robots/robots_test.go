@@ -0,0 +1,302 @@
+package robots
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/", "/", true},
+		{"/", "/anything", true},
+		{"/fish", "/fish", true},
+		{"/fish", "/fish.html", true},
+		{"/fish", "/fish/salmon.html", true},
+		{"/fish", "/Fish.asp", false},
+		{"/fish", "/catfish", false},
+		{"/fish/", "/fish", false},
+		{"/fish/", "/fish/", true},
+		{"/fish*", "/fishheads/yummy.html", true},
+		{"/*.php", "/index.php", true},
+		{"/*.php", "/folder/filename.php?parameters", true},
+		{"/*.php", "/windows.PHP", false},
+		{"/*.php$", "/filename.php", true},
+		{"/*.php$", "/filename.php?parameters", false},
+		{"/*.php$", "/filename.php5", false},
+		{"/fish*.php", "/fishheads/catfish.php?parameters", true},
+		{"/fish*.php", "/Fish.PHP", false},
+		{"/*/page", "/a/b/page", true},
+		{"/a*b*c", "/axxbyyc", true},
+		{"/a*b*c", "/axxcyyb", false},
+		{"/$", "/", true},
+		{"/$", "/index.html", false},
+		{"*", "/", true},
+		{"/page$x", "/page$x", true}, // $ only anchors at the end of the pattern
+	}
+
+	for _, tt := range tests {
+		if got := matches(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matches(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTestPrecedence(t *testing.T) {
+	rules := Parse(strings.NewReader(`
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Allow: /page
+Disallow: /*.html
+Disallow: /tie
+Allow: /tie
+Disallow: /search?
+Disallow: /caf%c3%a9
+`))
+
+	tests := []struct {
+		path     string
+		allowed  bool
+		wantRule string // Empty when no rule should match
+	}{
+		{"/", true, ""},
+		{"/private/secret", false, "Disallow: /private"},
+		{"/private/public/page", true, "Allow: /private/public"},
+		{"/page.html", false, "Disallow: /*.html"}, // Longer pattern wins over the shorter Allow
+		{"/page", true, "Allow: /page"},
+		{"/tie", true, "Allow: /tie"}, // Allow wins a tie
+		{"/search?q=go", false, "Disallow: /search?"},
+		{"/search", true, ""},
+		{"/café", false, "Disallow: /caf%C3%A9"}, // Percent-encoding is normalized on both sides
+		{"/caf%C3%A9", false, "Disallow: /caf%C3%A9"},
+		{"http://example.com/private/x?y=1", false, "Disallow: /private"},
+		{"/robots.txt", true, ""},
+	}
+
+	for _, tt := range tests {
+		verdict := rules.Test("Googlebot", tt.path)
+		if verdict.Allowed != tt.allowed {
+			t.Errorf("Test(%q).Allowed = %v, want %v (%s)", tt.path, verdict.Allowed, tt.allowed, verdict.Reason)
+		}
+		gotRule := ""
+		if verdict.Rule != nil {
+			gotRule = verdict.Rule.String()
+		}
+		if gotRule != tt.wantRule {
+			t.Errorf("Test(%q).Rule = %q, want %q", tt.path, gotRule, tt.wantRule)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	rules := Parse(strings.NewReader(`
+User-agent: *
+Disallow: /all
+Crawl-delay: 5
+
+User-agent: Googlebot
+User-agent: Bingbot
+Disallow: /search-engines
+
+User-agent: googlebot/2.1
+Disallow: /google-only
+Crawl-delay: 2
+
+User-agent: Googlebot-Image
+Disallow: /images
+`))
+
+	tests := []struct {
+		agent      string
+		wantAgents []string
+		wantRules  []string
+		crawlDelay time.Duration
+	}{
+		{
+			// Every group naming the product token is merged, in file order
+			agent:      "Googlebot/2.1 (+http://www.google.com/bot.html)",
+			wantAgents: []string{"googlebot"},
+			wantRules:  []string{"Disallow: /search-engines", "Disallow: /google-only"},
+			crawlDelay: 2 * time.Second,
+		},
+		{
+			agent:      "bingbot",
+			wantAgents: []string{"bingbot"},
+			wantRules:  []string{"Disallow: /search-engines"},
+		},
+		{
+			agent:      "Googlebot-Image",
+			wantAgents: []string{"googlebot-image"},
+			wantRules:  []string{"Disallow: /images"},
+		},
+		{
+			// Specific groups replace the * group instead of adding to it
+			agent:      "DuckDuckBot",
+			wantAgents: []string{"*"},
+			wantRules:  []string{"Disallow: /all"},
+			crawlDelay: 5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		group, ok := rules.group(tt.agent)
+		if !ok {
+			t.Errorf("group(%q) found no group", tt.agent)
+			continue
+		}
+		if strings.Join(group.Agents, ",") != strings.Join(tt.wantAgents, ",") {
+			t.Errorf("group(%q).Agents = %v, want %v", tt.agent, group.Agents, tt.wantAgents)
+		}
+		var gotRules []string
+		for _, rule := range group.Rules {
+			gotRules = append(gotRules, rule.String())
+		}
+		if strings.Join(gotRules, "|") != strings.Join(tt.wantRules, "|") {
+			t.Errorf("group(%q).Rules = %v, want %v", tt.agent, gotRules, tt.wantRules)
+		}
+		if group.CrawlDelay != tt.crawlDelay {
+			t.Errorf("group(%q).CrawlDelay = %s, want %s", tt.agent, group.CrawlDelay, tt.crawlDelay)
+		}
+	}
+
+	noWildcard := Parse(strings.NewReader("User-agent: Googlebot\nDisallow: /\n"))
+	if _, ok := noWildcard.group("Bingbot"); ok {
+		t.Error("group(Bingbot) matched without a * group")
+	}
+	if !noWildcard.Allowed("Bingbot", "/anything") {
+		t.Error("Bingbot should be allowed when no group applies")
+	}
+}
+
+func TestFetch(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantErr     bool
+		unreachable bool
+		allowed     bool // Whether /private may be crawled
+	}{
+		{name: "2xx", status: http.StatusOK, body: "User-agent: *\nDisallow: /private\n", allowed: false},
+		{name: "2xx empty", status: http.StatusOK, body: "", allowed: true},
+		{name: "4xx", status: http.StatusNotFound, allowed: true},
+		{name: "401", status: http.StatusUnauthorized, allowed: true},
+		{name: "5xx", status: http.StatusServiceUnavailable, wantErr: true, unreachable: true, allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/robots.txt" {
+					t.Errorf("fetched %s, want /robots.txt", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			rules, err := Fetch(context.Background(), server.Client(), server.URL+"/some/page")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, want error %v", err, tt.wantErr)
+			}
+			if rules == nil {
+				t.Fatal("Fetch() returned no rules")
+			}
+			if rules.Status != tt.status {
+				t.Errorf("Status = %d, want %d", rules.Status, tt.status)
+			}
+			if rules.Unreachable() != tt.unreachable {
+				t.Errorf("Unreachable() = %v, want %v", rules.Unreachable(), tt.unreachable)
+			}
+			if got := rules.Allowed("Googlebot", "/private"); got != tt.allowed {
+				t.Errorf("Allowed(/private) = %v, want %v", got, tt.allowed)
+			}
+		})
+	}
+
+	t.Run("redirects", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.Handle("/robots.txt", http.RedirectHandler("/moved/robots.txt", http.StatusMovedPermanently))
+		mux.HandleFunc("/moved/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		// A redirect to a file is followed
+		rules, err := Fetch(context.Background(), server.Client(), server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rules.Status != http.StatusOK || rules.Allowed("Googlebot", "/private") {
+			t.Errorf("redirected robots.txt: Status = %d, Allowed(/private) = true, want the moved file obeyed", rules.Status)
+		}
+	})
+
+	for _, tt := range []struct {
+		name    string
+		handler http.HandlerFunc
+		status  int
+	}{
+		{
+			name: "redirect loop",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/robots.txt", http.StatusFound)
+			},
+			status: http.StatusFound,
+		},
+		{
+			name: "3xx without Location",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusMultipleChoices)
+			},
+			status: http.StatusMultipleChoices,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+
+			// As for a 4xx, a robots.txt that cannot be reached through redirects allows everything
+			rules, err := Fetch(context.Background(), server.Client(), server.URL)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v, want none", err)
+			}
+			if rules.Status != tt.status {
+				t.Errorf("Status = %d, want %d", rules.Status, tt.status)
+			}
+			if rules.Unreachable() || !rules.Allowed("Googlebot", "/private") {
+				t.Error("an unresolved redirect should allow everything")
+			}
+			if got := requests.Load(); got > maxRedirects+1 {
+				t.Errorf("%d requests, want at most %d", got, maxRedirects+1)
+			}
+		})
+	}
+
+	t.Run("network error", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close() // Nothing listens any more
+
+		rules, err := Fetch(context.Background(), server.Client(), server.URL)
+		if err == nil {
+			t.Fatal("Fetch() succeeded against a closed server")
+		}
+		if rules == nil || !rules.Unreachable() || rules.Allowed("Googlebot", "/") {
+			t.Error("an unreachable robots.txt should disallow everything")
+		}
+	})
+}
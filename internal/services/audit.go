@@ -1,24 +1,195 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/ugolbck/seofordev/internal/audit"
 	"github.com/ugolbck/seofordev/internal/crawler"
+	"github.com/ugolbck/seofordev/internal/devserver"
+	"github.com/ugolbck/seofordev/internal/events"
 	"github.com/ugolbck/seofordev/internal/export"
+	"github.com/ugolbck/seofordev/internal/urlnorm"
 )
 
 // AuditConfig represents configuration for an audit
 type AuditConfig struct {
-	Port           int
-	Concurrency    int
-	MaxPages       int
-	MaxDepth       int
-	IgnorePatterns []string
+	Port            int
+	URL             string // Full base URL; overrides Port when set
+	Concurrency     int
+	MaxPages        int
+	MaxDepth        int
+	IgnorePatterns  []string // Exclude patterns: /regex/, globs such as /blog/** or substrings
+	IncludePatterns []string // When set, only matching URLs are audited
+	URLs            []string // List mode: audit exactly these URLs instead of crawling
+	Renderer        string
+	Insecure        bool
+	BlockResources  []string // Browser resource types not loaded, e.g. image, font, media
+	Rate            float64  // Maximum requests per second per host; 0 means unlimited
+	RobotsAgent     string   // robots.txt user-agent the crawl obeys; empty means the profile's, or Googlebot
+	Profile         string   // Built-in crawl profile (User-Agent, viewport, robots group); empty keeps renderer defaults
+	Device          string   // Emulated device: mobile, desktop or both; empty keeps the renderer's viewport
+	Screenshots     bool     // Save full-page and above-the-fold PNGs of each page (browser renderer only)
+	CompareRaw      bool     // Compare each render with the raw HTML to find content that needs JavaScript (browser renderer only)
+	RobotsFailOpen  bool     // Crawl without restrictions when robots.txt is unreachable or returns 5xx
+	Auth            AuthConfig
+	Budget          audit.PerformanceBudget // Performance limits; unset fields use the defaults
+	URLPolicy       urlnorm.Policy          // How URLs are canonicalized; the zero value is the default policy
+	Exec            string                  // Dev server command started before crawling and stopped afterwards
+	WaitFor         string                  // URL polled until the dev server responds; empty means the audited URL
+	WaitTimeout     time.Duration           // How long to wait for the dev server; 0 means defaultWaitTimeout
 }
 
+// AuthConfig describes how to authenticate against the audited site
+type AuthConfig struct {
+	Headers      map[string]string // Extra HTTP headers
+	BasicAuth    string            // "user:password"
+	CookieFile   string            // JSON or Netscape cookies.txt file
+	StorageState string            // Playwright storageState JSON file
+	LoginScript  string            // YAML/JSON login steps
+}
+
+// crawlOptions are the crawler settings resolved from an AuditConfig
+type crawlOptions struct {
+	auth    crawler.AuthOptions
+	profile *crawler.Profile // nil when no profile is used
+	devices []crawler.Device
+}
+
+// crawlOptions loads credentials and looks up the profile and devices, so invalid
+// settings are reported before an audit is started or resumed
+func (config AuditConfig) crawlOptions() (crawlOptions, error) {
+	var opts crawlOptions
+
+	auth, err := config.Auth.crawlerAuth()
+	if err != nil {
+		return opts, fmt.Errorf("invalid auth configuration: %w", err)
+	}
+	opts.auth = auth
+
+	if config.Profile != "" {
+		profile, err := crawler.LookupProfile(config.Profile)
+		if err != nil {
+			return opts, err
+		}
+		opts.profile = &profile
+	}
+
+	if opts.devices, err = crawler.ParseDevices(config.Device); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// crawlerAuth loads the auth files and converts the config for the crawler
+func (a AuthConfig) crawlerAuth() (crawler.AuthOptions, error) {
+	auth := crawler.AuthOptions{
+		Headers:          a.Headers,
+		StorageStatePath: a.StorageState,
+	}
+
+	if a.BasicAuth != "" {
+		basicAuth, err := crawler.ParseBasicAuth(a.BasicAuth)
+		if err != nil {
+			return auth, err
+		}
+		auth.BasicAuth = basicAuth
+	}
+
+	if a.CookieFile != "" {
+		cookies, err := crawler.LoadCookieFile(a.CookieFile)
+		if err != nil {
+			return auth, err
+		}
+		auth.Cookies = cookies
+	}
+
+	if a.StorageState != "" {
+		// Validate early; the browser reads the file itself
+		if _, err := crawler.LoadCookieFile(a.StorageState); err != nil {
+			return auth, fmt.Errorf("invalid storage state: %w", err)
+		}
+	}
+
+	if a.LoginScript != "" {
+		script, err := crawler.LoadLoginScript(a.LoginScript)
+		if err != nil {
+			return auth, err
+		}
+		auth.LoginScript = script
+	}
+
+	return auth, nil
+}
+
+// summary describes the auth setup without secrets, for storing with the audit
+func (a AuthConfig) summary() *audit.AuthSummary {
+	if len(a.Headers) == 0 && a.BasicAuth == "" && a.CookieFile == "" && a.StorageState == "" && a.LoginScript == "" {
+		return nil
+	}
+
+	summary := &audit.AuthSummary{
+		CookieFile:   a.CookieFile,
+		StorageState: a.StorageState,
+		LoginScript:  a.LoginScript,
+	}
+	for name := range a.Headers {
+		summary.HeaderNames = append(summary.HeaderNames, name)
+	}
+	sort.Strings(summary.HeaderNames)
+	if user, _, ok := strings.Cut(a.BasicAuth, ":"); ok {
+		summary.BasicAuthUser = user
+	}
+	return summary
+}
+
+// BaseURL returns the URL to audit: URL when set, otherwise localhost on Port
+func (c AuditConfig) BaseURL() (string, error) {
+	if c.URL == "" {
+		return fmt.Sprintf("http://localhost:%d", c.Port), nil
+	}
+
+	raw := c.URL
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw // Allow "staging.local:8080/docs" shorthand
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", c.URL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid URL %q: scheme must be http or https", c.URL)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("invalid URL %q: missing host", c.URL)
+	}
+
+	parsed.Fragment = ""
+	return parsed.String(), nil
+}
+
+// checkpointFile is the crawl checkpoint stored in each audit's directory
+const checkpointFile = "frontier.json"
+
+// screenshotsDir holds page screenshots in each audit's directory
+const screenshotsDir = "screenshots"
+
+// devServerLog captures the output of the --exec command in each audit's directory
+const devServerLog = "devserver.log"
+
+// defaultWaitTimeout is how long a dev server gets to start responding
+const defaultWaitTimeout = 60 * time.Second
+
 // AuditResult represents the result of a completed audit
 type AuditResult struct {
 	ID             string
@@ -26,12 +197,37 @@ type AuditResult struct {
 	CreatedAt      time.Time
 	CompletedAt    *time.Time
 	Status         string
+	Profile        string // Crawl profile the audit used; empty for renderer defaults
+	Device         string // Emulated device(s) the audit used; empty for renderer defaults
+	ScreenshotDir  string // Where page screenshots were saved; empty when not captured
+	ListedURLs     int    // URLs given in list mode; 0 for a crawl
+	DevServerLog   string // Output of the dev server the audit started; empty when none was
 	PagesAnalyzed  int
 	OverallScore   *float64
 	Pages          []PageResult
+	Sitemap        *SitemapCoverage
+	Crawl          *CrawlStats
 	Summary        *AuditSummary
 }
 
+// CrawlStats represents how much of the discovered site was crawled
+type CrawlStats struct {
+	PagesCrawled   int
+	NotCrawled     int // Discovered URLs skipped because of MaxPages
+	Duration       time.Duration
+	PagesPerSecond float64
+	CrawlDelay     time.Duration       // Crawl-delay from robots.txt
+	Throttled      int                 // 429/503 responses that made the crawler back off
+	Skipped        map[string][]string // Same-host URLs that were not crawled, by reason
+}
+
+// SitemapCoverage represents how sitemap entries compare with internal links
+type SitemapCoverage struct {
+	SitemapURLs  int
+	Unlinked     []string
+	NotInSitemap []string
+}
+
 // PageResult represents a single page's audit result
 type PageResult struct {
 	URL            string
@@ -49,6 +245,9 @@ type AuditSummary struct {
 // AuditService provides audit functionality
 type AuditService struct {
 	processor *audit.Processor
+
+	// OnEvent receives progress events from the crawler and the processor while an audit runs
+	OnEvent events.Handler
 }
 
 // NewAuditService creates a new audit service
@@ -63,16 +262,66 @@ func NewAuditService() (*AuditService, error) {
 	}, nil
 }
 
-// RunAudit performs a complete audit
-func (s *AuditService) RunAudit(baseURL string, config AuditConfig) (*AuditResult, error) {
+// RunAudit performs a complete audit. Cancelling ctx stops the crawl; pages already
+// fetched are analyzed and the audit is saved with the "partial" status.
+func (s *AuditService) RunAudit(ctx context.Context, baseURL string, config AuditConfig) (*AuditResult, error) {
+	// Load credentials and reject invalid setups before an audit record is created
+	opts, err := config.crawlOptions()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := crawler.NewFetcher(config.Renderer, crawler.FetcherOptions{Auth: opts.auth, BlockResources: config.BlockResources}); err != nil {
+		return nil, err
+	}
+	if _, err := crawler.CompilePatterns(config.IncludePatterns); err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	if _, err := crawler.CompilePatterns(config.IgnorePatterns); err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+	if config.Screenshots && config.Renderer == crawler.RendererHTTP {
+		return nil, fmt.Errorf("screenshots need the %s renderer", crawler.RendererBrowser)
+	}
+	if config.CompareRaw && config.Renderer == crawler.RendererHTTP {
+		return nil, fmt.Errorf("comparing with the raw HTML needs the %s renderer", crawler.RendererBrowser)
+	}
+	if config.WaitFor != "" {
+		if config.Exec == "" {
+			return nil, fmt.Errorf("waiting for a URL needs a dev server command to start")
+		}
+		if u, err := url.Parse(config.WaitFor); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid wait-for URL %q (expected http:// or https://)", config.WaitFor)
+		}
+	}
 
 	// Convert config
+	budget := config.Budget.WithDefaults()
 	auditConfig := audit.AuditConfig{
-		Port:           config.Port,
-		Concurrency:    config.Concurrency,
-		MaxPages:       config.MaxPages,
-		MaxDepth:       config.MaxDepth,
-		IgnorePatterns: config.IgnorePatterns,
+		Port:            config.Port,
+		URL:             config.URL,
+		Concurrency:     config.Concurrency,
+		MaxPages:        config.MaxPages,
+		MaxDepth:        config.MaxDepth,
+		IgnorePatterns:  config.IgnorePatterns,
+		IncludePatterns: config.IncludePatterns,
+		ListMode:        len(config.URLs) > 0,
+		URLs:            config.URLs,
+		Renderer:        config.Renderer,
+		Insecure:        config.Insecure,
+		BlockResources:  config.BlockResources,
+		Rate:            config.Rate,
+		RobotsAgent:     config.RobotsAgent,
+		Profile:         config.Profile,
+		Device:          config.Device,
+		Screenshots:     config.Screenshots,
+		CompareRaw:      config.CompareRaw,
+		RobotsFailOpen:  config.RobotsFailOpen,
+		Budget:          &budget,
+		URLPolicy:       &config.URLPolicy,
+		Auth:            config.Auth.summary(),
+		Exec:            config.Exec,
+		WaitFor:         config.WaitFor,
+		WaitTimeout:     config.WaitTimeout.Seconds(),
 	}
 
 	// Start audit
@@ -81,61 +330,316 @@ func (s *AuditService) RunAudit(baseURL string, config AuditConfig) (*AuditResul
 		return nil, fmt.Errorf("failed to start audit: %w", err)
 	}
 
+	return s.crawlAndAnalyze(ctx, localAudit.ID, baseURL, config, opts, nil, nil)
+}
+
+// ResumeAudit continues an interrupted audit from its last crawl checkpoint,
+// using the configuration it was started with. Secrets are not stored with the
+// audit, so headers and basic auth credentials must be provided again.
+func (s *AuditService) ResumeAudit(ctx context.Context, auditID string, authConfig AuthConfig) (*AuditResult, error) {
+	existing, err := s.GetAudit(auditID)
+	if err != nil {
+		return nil, err
+	}
+
+	localAudit, err := s.processor.GetAuditStatus(existing.ID)
+	if err != nil {
+		return nil, err
+	}
+	if localAudit.Status == "completed" {
+		return nil, fmt.Errorf("audit %s is already completed", localAudit.ID)
+	}
+
+	stored := localAudit.Config
+	config := AuditConfig{
+		Port:            stored.Port,
+		URL:             stored.URL,
+		Concurrency:     stored.Concurrency,
+		MaxPages:        stored.MaxPages,
+		MaxDepth:        stored.MaxDepth,
+		IgnorePatterns:  stored.IgnorePatterns,
+		IncludePatterns: stored.IncludePatterns,
+		URLs:            stored.URLs,
+		Renderer:        stored.Renderer,
+		Insecure:        stored.Insecure,
+		BlockResources:  stored.BlockResources,
+		Rate:            stored.Rate,
+		RobotsAgent:     stored.RobotsAgent,
+		Profile:         stored.Profile,
+		Device:          stored.Device,
+		Screenshots:     stored.Screenshots,
+		CompareRaw:      stored.CompareRaw,
+		RobotsFailOpen:  stored.RobotsFailOpen,
+		Auth:            authConfig,
+		Exec:            stored.Exec,
+		WaitFor:         stored.WaitFor,
+		WaitTimeout:     time.Duration(stored.WaitTimeout * float64(time.Second)),
+	}
+	if stored.Budget != nil {
+		config.Budget = *stored.Budget
+	}
+	if stored.URLPolicy != nil {
+		config.URLPolicy = *stored.URLPolicy
+	}
+	if err := config.Auth.restore(stored.Auth); err != nil {
+		return nil, err
+	}
+
+	opts, err := config.crawlOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Pages analyzed before the interruption are kept; the rest are crawled again
+	analyzed := make(map[string]bool, len(localAudit.Pages))
+	for _, page := range localAudit.Pages {
+		analyzed[page.URL] = true
+	}
+
+	dir, err := s.processor.AuditDir(localAudit.ID)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := crawler.LoadCheckpoint(filepath.Join(dir, checkpointFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if checkpoint == nil {
+		log.Warn("No crawl checkpoint found, crawling again from the start", "audit_id", localAudit.ID)
+	}
+
+	if _, err := s.processor.ResumeAudit(localAudit.ID); err != nil {
+		return nil, err
+	}
+
+	return s.crawlAndAnalyze(ctx, localAudit.ID, localAudit.BaseURL, config, opts, checkpoint, analyzed)
+}
+
+// restore fills in auth settings recorded with the audit that were not provided again.
+// Secrets are never stored, so missing header values or passwords are an error.
+func (a *AuthConfig) restore(summary *audit.AuthSummary) error {
+	if summary == nil {
+		return nil
+	}
+
+	for _, name := range summary.HeaderNames {
+		if _, ok := a.Headers[name]; !ok {
+			return fmt.Errorf("audit was run with header %q; pass it again with --header", name)
+		}
+	}
+	if summary.BasicAuthUser != "" && a.BasicAuth == "" {
+		return fmt.Errorf("audit was run with basic auth as %q; pass --basic-auth again", summary.BasicAuthUser)
+	}
+
+	if a.CookieFile == "" {
+		a.CookieFile = summary.CookieFile
+	}
+	if a.StorageState == "" {
+		a.StorageState = summary.StorageState
+	}
+	if a.LoginScript == "" {
+		a.LoginScript = summary.LoginScript
+	}
+
+	return nil
+}
+
+// startDevServer runs the --exec command and waits until it serves the wait-for
+// URL, or the audited URL when none was given. The server is stopped again if it
+// never becomes ready.
+func startDevServer(ctx context.Context, config AuditConfig, baseURL, logPath string) (*devserver.Server, error) {
+	waitFor := config.WaitFor
+	if waitFor == "" {
+		waitFor = baseURL
+	}
+	timeout := config.WaitTimeout
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+
+	log.Info("Starting dev server", "command", config.Exec, "log", logPath)
+	server, err := devserver.Start(config.Exec, logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := server.Wait(ctx, waitFor, timeout); err != nil {
+		if stopErr := server.Stop(); stopErr != nil {
+			log.Warn("Failed to stop dev server", "error", stopErr)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("interrupted while waiting for the dev server")
+		}
+		return nil, fmt.Errorf("dev server did not start (see %s): %w", logPath, err)
+	}
+	log.Info("Dev server is ready", "url", waitFor)
+
+	return server, nil
+}
+
+// crawlAndAnalyze crawls the site, submits the pages for analysis and waits for
+// the audit to complete. The crawl continues from checkpoint when one is given.
+// If ctx is cancelled the audit is finalized as partial and its checkpoint kept.
+func (s *AuditService) crawlAndAnalyze(ctx context.Context, auditID, baseURL string, config AuditConfig, opts crawlOptions, checkpoint *crawler.Checkpoint, analyzed map[string]bool) (result *AuditResult, err error) {
+	// Every error below leaves the audit finalized instead of stuck running
+	finalized := false
+	defer func() {
+		if err != nil && !finalized {
+			s.finalizeFailedAudit(ctx, auditID)
+		}
+	}()
+
+	dir, err := s.processor.AuditDir(auditID)
+	if err != nil {
+		return nil, err
+	}
+	checkpointPath := filepath.Join(dir, checkpointFile)
+
+	if config.Exec != "" {
+		server, err := startDevServer(ctx, config, baseURL, filepath.Join(dir, devServerLog))
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := server.Stop(); err != nil {
+				log.Warn("Failed to stop dev server", "error", err)
+			}
+		}()
+	}
 
 	// Create and run crawler
 	c := crawler.NewCrawler(
+		ctx,
 		baseURL,
 		config.Concurrency,
 		config.MaxPages,
 		config.MaxDepth,
 		config.IgnorePatterns,
 	)
+	if config.Renderer != "" {
+		c.Renderer = config.Renderer
+	}
+	c.Insecure = config.Insecure
+	c.BlockResources = config.BlockResources
+	c.Rate = config.Rate
+	if opts.profile != nil {
+		c.Profile = opts.profile
+		c.RobotsAgent = opts.profile.RobotsAgent
+	}
+	if config.RobotsAgent != "" {
+		c.RobotsAgent = config.RobotsAgent
+	}
+	c.Devices = opts.devices
+	c.Auth = opts.auth
+	c.CheckpointPath = checkpointPath
+	if config.Screenshots {
+		c.ScreenshotDir = filepath.Join(dir, screenshotsDir)
+	}
+	c.CompareRaw = config.CompareRaw
+	c.RobotsFailOpen = config.RobotsFailOpen
+	c.URLPolicy = config.URLPolicy
+	c.IncludePatterns = config.IncludePatterns
+	c.URLs = config.URLs
+	s.processor.Budget = config.Budget
+	c.OnEvent = s.OnEvent
+	s.processor.OnEvent = s.OnEvent
+	if checkpoint != nil {
+		c.Resume(checkpoint, analyzed)
+	}
+
+	// Analyze pages while the crawl is still running
+	analysisDone := make(chan error, 1)
+	go func() {
+		err := s.processor.SubmitPages(ctx, auditID, c.Pages())
+		// SubmitPages may stop early on an error or an interrupt; keep draining
+		// so the crawler never blocks sending a page and Start can return
+		for range c.Pages() {
+		}
+		analysisDone <- err
+	}()
+
+	crawlErr := c.Start()
+	analysisErr := <-analysisDone
 
-	if err := c.Start(); err != nil {
-		return nil, fmt.Errorf("crawling failed: %w", err)
+	// An interrupted audit keeps everything analyzed so far
+	interrupted := ctx.Err() != nil
+	if analysisErr != nil && !interrupted {
+		return nil, fmt.Errorf("failed to analyze pages: %w", analysisErr)
+	}
+	if crawlErr != nil && !interrupted {
+		return nil, fmt.Errorf("crawling failed: %w", crawlErr)
 	}
 
-	crawlResults := c.GetResults()
-	if len(crawlResults) == 0 {
+	stats := c.GetCrawlStats()
+	if stats.PagesCrawled == 0 && !interrupted {
+		if rules := c.Robots(); rules != nil && rules.Unreachable() {
+			problem := "is unreachable"
+			if rules.Status != 0 {
+				problem = fmt.Sprintf("returned %d", rules.Status)
+			}
+			return nil, fmt.Errorf("%s %s, which RFC 9309 treats as disallowing everything, so nothing was crawled "+
+				"(fix the robots.txt route or pass --ignore-robots-errors to crawl anyway)", rules.URL, problem)
+		}
+		if rules := c.Robots(); rules != nil && !rules.Allowed(c.RobotsAgent, baseURL) {
+			verdict := rules.Test(c.RobotsAgent, baseURL)
+			return nil, fmt.Errorf("robots.txt blocks %s for %s: %s (run 'seo robots test %s' for details)",
+				baseURL, c.RobotsAgent, verdict.Reason, baseURL)
+		}
+		if len(config.URLs) > 0 {
+			return nil, fmt.Errorf("none of the %d listed URLs could be crawled on %s - check if the site is running", len(config.URLs), baseURL)
+		}
 		return nil, fmt.Errorf("no pages found on %s - check if the site is running", baseURL)
 	}
 
+	// Record sitemap coverage before completing so it is part of the summary.
+	// List mode reads no sitemaps, so there is no coverage to report.
+	if len(config.URLs) == 0 {
+		if err := s.processor.SetSitemapCoverage(auditID, c.GetSitemapCoverage()); err != nil {
+			log.Warn("Failed to save sitemap coverage", "error", err)
+		}
+	}
+	if err := s.processor.SetCrawlStats(auditID, stats); err != nil {
+		log.Warn("Failed to save crawl stats", "error", err)
+	}
 
-	// Submit pages for analysis
-	if err := s.processor.SubmitPages(localAudit.ID, crawlResults); err != nil {
-		return nil, fmt.Errorf("failed to submit pages: %w", err)
+	status := audit.StatusCompleted
+	if interrupted {
+		status = audit.StatusPartial
+	}
+	if err := s.processor.CompleteAudit(auditID, status); err != nil {
+		return nil, err
 	}
+	finalized = true
 
-	// Wait for completion (poll status)
-	return s.waitForCompletion(localAudit.ID)
-}
+	result, err = s.GetAudit(auditID)
+	if err != nil {
+		return nil, err
+	}
 
-// waitForCompletion polls the audit status until complete
-func (s *AuditService) waitForCompletion(auditID string) (*AuditResult, error) {
-	for {
-		status, err := s.processor.GetAuditStatus(auditID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get audit status: %w", err)
+	// The checkpoint is only needed to resume an unfinished audit
+	if !interrupted {
+		if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("Failed to remove crawl checkpoint", "error", err)
 		}
+	}
 
-		log.Debug("Audit progress",
-			"status", status.Status,
-			"pages_analyzed", status.PagesAnalyzed,
-			"total_pages", len(status.Pages))
-
-		if status.Status == "completed" {
-			break
-		} else if status.Status == "failed" {
-			return nil, fmt.Errorf("audit failed")
-		}
+	return result, nil
+}
 
-		// Poll every 2 seconds
-		time.Sleep(2 * time.Second)
+// finalizeFailedAudit records the end of a run that returned an error. Audits with
+// analyzed pages, or interrupted ones, are kept as partial so they can be resumed;
+// the others are marked failed.
+func (s *AuditService) finalizeFailedAudit(ctx context.Context, auditID string) {
+	status := audit.StatusFailed
+	if ctx.Err() != nil {
+		status = audit.StatusPartial
+	} else if current, err := s.processor.GetAuditStatus(auditID); err == nil && current.PagesAnalyzed > 0 {
+		status = audit.StatusPartial
 	}
 
-	// Complete the audit (note: completeAudit is called internally by the processor)
-	// Load final results
-	return s.GetAudit(auditID)
+	if err := s.processor.CompleteAudit(auditID, status); err != nil {
+		log.Warn("Failed to finalize audit", "audit_id", auditID, "error", err)
+	}
 }
 
 // ListAudits returns all stored audits
@@ -233,6 +737,7 @@ func (s *AuditService) ExportAuditPrompt(auditID string) (string, error) {
 				Checks             []export.SEOCheckResponse `json:"checks"`
 				AnalyzedAt         string                   `json:"analyzed_at,omitempty"`
 				IssuesCount        int                      `json:"issues_count"`
+				RuntimeErrors      []string                 `json:"runtime_errors,omitempty"`
 			}{
 				URL:                details.URL,
 				StatusCode:         details.StatusCode,
@@ -247,6 +752,7 @@ func (s *AuditService) ExportAuditPrompt(auditID string) (string, error) {
 				IndexabilityReason: details.IndexabilityReason,
 				Checks:             s.convertChecks(details.Checks),
 				IssuesCount:        details.IssuesCount,
+				RuntimeErrors:      runtimeErrors(details),
 			},
 		}
 
@@ -277,6 +783,33 @@ func (s *AuditService) convertLocalAudit(audit *audit.LocalAudit) *AuditResult {
 		}
 	}
 
+	var sitemap *SitemapCoverage
+	if audit.Sitemap != nil {
+		sitemap = &SitemapCoverage{
+			SitemapURLs:  audit.Sitemap.SitemapURLs,
+			Unlinked:     audit.Sitemap.Unlinked,
+			NotInSitemap: audit.Sitemap.NotInSitemap,
+		}
+	}
+
+	var crawl *CrawlStats
+	if audit.Crawl != nil {
+		crawl = &CrawlStats{
+			PagesCrawled:   audit.Crawl.PagesCrawled,
+			NotCrawled:     audit.Crawl.NotCrawled,
+			Duration:       time.Duration(audit.Crawl.DurationSeconds * float64(time.Second)),
+			PagesPerSecond: audit.Crawl.PagesPerSecond,
+			CrawlDelay:     time.Duration(audit.Crawl.CrawlDelay * float64(time.Second)),
+			Throttled:      audit.Crawl.Throttled,
+		}
+		if len(audit.Crawl.Skipped) > 0 {
+			crawl.Skipped = make(map[string][]string)
+			for _, skip := range audit.Crawl.Skipped {
+				crawl.Skipped[skip.Reason] = append(crawl.Skipped[skip.Reason], skip.URL)
+			}
+		}
+	}
+
 	var summary *AuditSummary
 	if audit.Summary != nil {
 		summary = &AuditSummary{
@@ -285,19 +818,63 @@ func (s *AuditService) convertLocalAudit(audit *audit.LocalAudit) *AuditResult {
 		}
 	}
 
+	var screenshotDir string
+	if audit.Config.Screenshots {
+		if dir, err := s.processor.AuditDir(audit.ID); err == nil {
+			screenshotDir = filepath.Join(dir, screenshotsDir)
+		}
+	}
+
+	var devServerLogPath string
+	if audit.Config.Exec != "" {
+		if dir, err := s.processor.AuditDir(audit.ID); err == nil {
+			devServerLogPath = filepath.Join(dir, devServerLog)
+		}
+	}
+
 	return &AuditResult{
 		ID:             audit.ID,
 		BaseURL:        audit.BaseURL,
 		CreatedAt:      audit.CreatedAt,
 		CompletedAt:    audit.CompletedAt,
 		Status:         audit.Status,
+		Profile:        audit.Config.Profile,
+		Device:         audit.Config.Device,
+		ScreenshotDir:  screenshotDir,
+		ListedURLs:     len(audit.Config.URLs),
+		DevServerLog:   devServerLogPath,
 		PagesAnalyzed:  len(audit.Pages),
 		OverallScore:   audit.OverallScore,
 		Pages:          pages,
+		Sitemap:        sitemap,
+		Crawl:          crawl,
 		Summary:        summary,
 	}
 }
 
+// runtimeErrors lists a page's JavaScript errors and failed requests for the export prompt
+func runtimeErrors(page *audit.LocalPageAnalysis) []string {
+	var lines []string
+	for _, message := range page.PageErrors {
+		lines = append(lines, "Uncaught exception: "+message)
+	}
+	for _, entry := range page.ConsoleErrors {
+		line := "Console error: " + entry.Message
+		if entry.Location != "" {
+			line += " (at " + entry.Location + ")"
+		}
+		lines = append(lines, line)
+	}
+	for _, request := range page.FailedRequests {
+		reason := request.Error
+		if request.StatusCode != 0 {
+			reason = fmt.Sprintf("HTTP %d", request.StatusCode)
+		}
+		lines = append(lines, fmt.Sprintf("Failed %s request: %s (%s)", request.ResourceType, request.URL, reason))
+	}
+	return lines
+}
+
 // convertChecks converts map[string]audit.CheckResult to []export.SEOCheckResponse
 func (s *AuditService) convertChecks(checks map[string]audit.CheckResult) []export.SEOCheckResponse {
 	result := make([]export.SEOCheckResponse, 0, len(checks))
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ugolbck/seofordev/internal/audit"
+	"github.com/ugolbck/seofordev/internal/crawler"
+	"github.com/ugolbck/seofordev/internal/events"
+)
+
+// testSitePages is the size of the site served by newTestSite
+const testSitePages = 15
+
+// newTestSite serves / and /page/1 .. /page/n-1 as a binary tree
+func newTestSite(t *testing.T) *httptest.Server {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		index := 0
+		if r.URL.Path != "/" {
+			number, ok := strings.CutPrefix(r.URL.Path, "/page/")
+			var err error
+			if index, err = strconv.Atoi(number); !ok || err != nil || index <= 0 || index >= testSitePages {
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		var links strings.Builder
+		for _, child := range []int{2*index + 1, 2*index + 2} {
+			if child < testSitePages {
+				fmt.Fprintf(&links, `<a href="/page/%d">Page %d</a>`, child, child)
+			}
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, `<!DOCTYPE html><html lang="en"><head><title>Page %d</title></head><body><h1>Page %d</h1>%s</body></html>`,
+			index, index, links.String())
+	}))
+	t.Cleanup(site.Close)
+	return site
+}
+
+// newTestService stores audits in a temporary home directory
+func newTestService(t *testing.T) *AuditService {
+	t.Setenv("HOME", t.TempDir())
+	service, err := NewAuditService()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+var testConfig = AuditConfig{Renderer: crawler.RendererHTTP, Concurrency: 1}
+
+// interruptedAudit runs an audit that is cancelled after a few pages were analyzed
+func interruptedAudit(t *testing.T, service *AuditService, baseURL string) *AuditResult {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var analyzed atomic.Int32
+	service.OnEvent = func(event events.Event) {
+		if event.Type == events.PageAnalyzed && analyzed.Add(1) == 3 {
+			cancel()
+		}
+	}
+	defer func() { service.OnEvent = nil }()
+
+	result, err := service.RunAudit(ctx, baseURL, testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != string(audit.StatusPartial) {
+		t.Fatalf("interrupted audit status = %q, want partial", result.Status)
+	}
+	if result.PagesAnalyzed >= testSitePages {
+		t.Fatalf("interrupted audit analyzed all %d pages", result.PagesAnalyzed)
+	}
+	return result
+}
+
+func TestResumeAudit(t *testing.T) {
+	service := newTestService(t)
+	site := newTestSite(t)
+
+	partial := interruptedAudit(t, service, site.URL)
+
+	result, err := service.ResumeAudit(context.Background(), partial.ID, AuthConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != string(audit.StatusCompleted) {
+		t.Errorf("resumed audit status = %q, want completed", result.Status)
+	}
+
+	seen := make(map[string]bool)
+	for _, page := range result.Pages {
+		if seen[page.URL] {
+			t.Errorf("%s was stored twice", page.URL)
+		}
+		seen[page.URL] = true
+	}
+	if len(seen) != testSitePages {
+		t.Errorf("resumed audit has %d pages, want %d", len(seen), testSitePages)
+	}
+
+	dir, err := service.processor.AuditDir(partial.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, checkpointFile)); !os.IsNotExist(err) {
+		t.Error("checkpoint was kept after the audit completed")
+	}
+
+	if _, err := service.ResumeAudit(context.Background(), partial.ID, AuthConfig{}); err == nil {
+		t.Error("resuming a completed audit should fail")
+	}
+}
+
+func TestResumeAuditCheckpointErrors(t *testing.T) {
+	service := newTestService(t)
+	site := newTestSite(t)
+
+	t.Run("corrupt checkpoint", func(t *testing.T) {
+		partial := interruptedAudit(t, service, site.URL)
+		dir, err := service.processor.AuditDir(partial.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, checkpointFile), []byte(`{"pending": [`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := service.ResumeAudit(context.Background(), partial.ID, AuthConfig{}); err == nil || !strings.Contains(err.Error(), "invalid checkpoint") {
+			t.Fatalf("ResumeAudit() error = %v, want an invalid checkpoint error", err)
+		}
+		stored, err := service.GetAudit(partial.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Status != string(audit.StatusPartial) {
+			t.Errorf("status after a failed resume = %q, want it left partial", stored.Status)
+		}
+	})
+
+	t.Run("missing checkpoint", func(t *testing.T) {
+		partial := interruptedAudit(t, service, site.URL)
+		dir, err := service.processor.AuditDir(partial.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(filepath.Join(dir, checkpointFile)); err != nil {
+			t.Fatal(err)
+		}
+
+		// The crawl starts over; pages analyzed before are kept, not stored twice
+		result, err := service.ResumeAudit(context.Background(), partial.ID, AuthConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Status != string(audit.StatusCompleted) || len(result.Pages) != testSitePages {
+			t.Errorf("resumed audit = %s with %d pages, want completed with %d", result.Status, len(result.Pages), testSitePages)
+		}
+	})
+}
+
+func TestRunAuditFinalizesFailures(t *testing.T) {
+	service := newTestService(t)
+
+	// Nothing listens on down any more
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	tests := []struct {
+		name    string
+		site    http.HandlerFunc
+		config  AuditConfig
+		wantErr string
+	}{
+		{
+			name: "robots.txt blocks the site",
+			site: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/robots.txt" {
+					fmt.Fprint(w, "User-agent: *\nDisallow: /\n")
+					return
+				}
+				fmt.Fprint(w, "<html><body>Home</body></html>")
+			},
+			wantErr: "robots.txt blocks",
+		},
+		{
+			name: "robots.txt unreachable",
+			site: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			wantErr: "--ignore-robots-errors",
+		},
+		{
+			name:    "dev server exits",
+			site:    http.NotFound,
+			config:  AuditConfig{Exec: "exit 3", WaitFor: down.URL, WaitTimeout: 5 * time.Second},
+			wantErr: "dev server did not start",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			site := httptest.NewServer(tt.site)
+			defer site.Close()
+
+			config := tt.config
+			config.Renderer = testConfig.Renderer
+			config.Concurrency = testConfig.Concurrency
+
+			before, _ := service.ListAudits()
+			_, err := service.RunAudit(context.Background(), site.URL, config)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RunAudit() error = %v, want %q", err, tt.wantErr)
+			}
+
+			audits, err := service.ListAudits()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(audits) != len(before)+1 {
+				t.Fatalf("%d audits stored, want %d", len(audits), len(before)+1)
+			}
+			for _, stored := range audits {
+				if stored.BaseURL == site.URL && stored.Status != string(audit.StatusFailed) {
+					t.Errorf("audit status = %q, want failed", stored.Status)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid profile", func(t *testing.T) {
+		before, _ := service.ListAudits()
+		config := testConfig
+		config.Profile = "no-such-profile"
+		if _, err := service.RunAudit(context.Background(), "http://localhost:3000", config); err == nil {
+			t.Fatal("RunAudit() accepted an unknown profile")
+		}
+		if audits, _ := service.ListAudits(); len(audits) != len(before) {
+			t.Error("an audit was created for an invalid configuration")
+		}
+	})
+}
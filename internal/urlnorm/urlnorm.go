@@ -0,0 +1,149 @@
+// Package urlnorm canonicalizes URLs so that variants of the same page, such as
+// tracking-parameter or reordered query strings, compare equal.
+package urlnorm
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// TrackingParams are the query parameters removed unless a policy keeps them.
+// A trailing * matches any parameter starting with the prefix.
+var TrackingParams = []string{
+	"utm_*",
+	"gclid",
+	"gbraid",
+	"wbraid",
+	"dclid",
+	"fbclid",
+	"msclkid",
+	"twclid",
+	"yclid",
+	"mc_cid",
+	"mc_eid",
+	"_ga",
+	"_gl",
+	"_hsenc",
+	"_hsmi",
+}
+
+// indexFiles are the directory index documents folded into their directory
+var indexFiles = []string{"index.html", "index.htm", "index.php"}
+
+// Policy describes how URLs are canonicalized. The zero value is the default
+// policy: tracking parameters are removed, query parameters are sorted, paths
+// keep their case and index documents are kept.
+type Policy struct {
+	StripParams     []string `json:"strip_params,omitempty"`     // Extra query parameters removed; a trailing * matches a prefix
+	KeepTracking    bool     `json:"keep_tracking,omitempty"`    // Keep TrackingParams
+	KeepQueryOrder  bool     `json:"keep_query_order,omitempty"` // Don't sort query parameters
+	CaseInsensitive bool     `json:"case_insensitive,omitempty"` // Lowercase paths, for servers that ignore case
+	FoldIndex       bool     `json:"fold_index,omitempty"`       // Treat /dir/index.html as /dir
+}
+
+// Normalize canonicalizes a URL with the default policy
+func Normalize(rawURL string) string {
+	return Policy{}.Normalize(rawURL)
+}
+
+// Normalize removes the fragment and trailing slash, lowercases the host and
+// applies the policy's query and path rules. URLs that don't parse are
+// returned unchanged.
+func (p Policy) Normalize(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	parsed.Host = strings.ToLower(parsed.Host)
+
+	if p.CaseInsensitive {
+		parsed.Path = strings.ToLower(parsed.Path)
+		parsed.RawPath = ""
+	}
+	if p.FoldIndex {
+		for _, index := range indexFiles {
+			if path.Base(parsed.Path) == index {
+				parsed.Path = strings.TrimSuffix(parsed.Path, index)
+				parsed.RawPath = ""
+				break
+			}
+		}
+	}
+
+	// Root is always "/", other paths lose their trailing slash
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	} else if parsed.Path != "/" && strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+		parsed.RawPath = strings.TrimSuffix(parsed.RawPath, "/")
+	}
+
+	parsed.RawQuery = p.normalizeQuery(parsed.RawQuery)
+	parsed.ForceQuery = false
+
+	return parsed.String()
+}
+
+// normalizeQuery strips and sorts the query parameters. Pairs are kept in their
+// original encoding so values are not re-escaped.
+func (p Policy) normalizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	var pairs []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		if p.strips(queryKey(pair)) {
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+
+	if !p.KeepQueryOrder {
+		// Stable, so repeated keys keep their relative order
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return queryKey(pairs[i]) < queryKey(pairs[j])
+		})
+	}
+	return strings.Join(pairs, "&")
+}
+
+// strips reports whether a query parameter is removed
+func (p Policy) strips(key string) bool {
+	if !p.KeepTracking && matchesParam(TrackingParams, key) {
+		return true
+	}
+	return matchesParam(p.StripParams, key)
+}
+
+func matchesParam(patterns []string, key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// queryKey returns the decoded key of a key=value query pair
+func queryKey(pair string) string {
+	key, _, _ := strings.Cut(pair, "=")
+	if decoded, err := url.QueryUnescape(key); err == nil {
+		return decoded
+	}
+	return key
+}
@@ -0,0 +1,142 @@
+package urlnorm
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"root gets a slash", "http://example.com", "http://example.com/"},
+		{"trailing slash removed", "http://example.com/docs/", "http://example.com/docs"},
+		{"fragment removed", "http://example.com/docs#install", "http://example.com/docs"},
+		{"host lowercased", "http://Example.COM:8080/Docs", "http://example.com:8080/Docs"},
+		{"path case kept", "http://example.com/About", "http://example.com/About"},
+		{"empty query removed", "http://example.com/search?", "http://example.com/search"},
+		{"query sorted", "http://example.com/p?b=2&a=1&c=3", "http://example.com/p?a=1&b=2&c=3"},
+		{"repeated keys keep their order", "http://example.com/p?tag=z&id=1&tag=a", "http://example.com/p?id=1&tag=z&tag=a"},
+		{"utm parameters removed", "http://example.com/p?utm_source=x&id=1&utm_medium=y", "http://example.com/p?id=1"},
+		{"click ids removed", "http://example.com/p?gclid=1&fbclid=2&msclkid=3", "http://example.com/p"},
+		{"tracking match ignores case", "http://example.com/p?UTM_Source=x&id=1", "http://example.com/p?id=1"},
+		{"similar names kept", "http://example.com/p?utmost=1&gclids=2", "http://example.com/p?gclids=2&utmost=1"},
+		{"encoded values not re-escaped", "http://example.com/p?q=a%20b&z=%2F", "http://example.com/p?q=a%20b&z=%2F"},
+		{"encoded keys sorted by decoded name", "http://example.com/p?%62=2&a=1", "http://example.com/p?a=1&%62=2"},
+		{"empty pairs dropped", "http://example.com/p?a=1&&b=2&", "http://example.com/p?a=1&b=2"},
+		{"index kept by default", "http://example.com/docs/index.html", "http://example.com/docs/index.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		in     string
+		want   string
+	}{
+		{
+			name:   "strip exact parameter",
+			policy: Policy{StripParams: []string{"sessionid"}},
+			in:     "http://example.com/p?sessionid=abc&id=1",
+			want:   "http://example.com/p?id=1",
+		},
+		{
+			name:   "strip parameter prefix",
+			policy: Policy{StripParams: []string{"ref_*"}},
+			in:     "http://example.com/p?ref_src=a&ref_id=b&referrer=c",
+			want:   "http://example.com/p?referrer=c",
+		},
+		{
+			name:   "keep tracking",
+			policy: Policy{KeepTracking: true},
+			in:     "http://example.com/p?utm_source=x&id=1",
+			want:   "http://example.com/p?id=1&utm_source=x",
+		},
+		{
+			name:   "keep tracking still strips configured parameters",
+			policy: Policy{KeepTracking: true, StripParams: []string{"sort"}},
+			in:     "http://example.com/p?sort=asc&gclid=1",
+			want:   "http://example.com/p?gclid=1",
+		},
+		{
+			name:   "keep query order",
+			policy: Policy{KeepQueryOrder: true},
+			in:     "http://example.com/p?b=2&utm_source=x&a=1",
+			want:   "http://example.com/p?b=2&a=1",
+		},
+		{
+			name:   "case insensitive paths",
+			policy: Policy{CaseInsensitive: true},
+			in:     "http://Example.com/Docs/Getting-Started/?Q=Go",
+			want:   "http://example.com/docs/getting-started?Q=Go",
+		},
+		{
+			name:   "fold index in a directory",
+			policy: Policy{FoldIndex: true},
+			in:     "http://example.com/docs/index.html",
+			want:   "http://example.com/docs",
+		},
+		{
+			name:   "fold index at the root",
+			policy: Policy{FoldIndex: true},
+			in:     "http://example.com/index.php?page=2",
+			want:   "http://example.com/?page=2",
+		},
+		{
+			name:   "fold index.htm",
+			policy: Policy{FoldIndex: true},
+			in:     "http://example.com/a/index.htm#top",
+			want:   "http://example.com/a",
+		},
+		{
+			name:   "fold only whole file names",
+			policy: Policy{FoldIndex: true},
+			in:     "http://example.com/docs/myindex.html",
+			want:   "http://example.com/docs/myindex.html",
+		},
+		{
+			name:   "fold index case insensitively",
+			policy: Policy{FoldIndex: true, CaseInsensitive: true},
+			in:     "http://example.com/Docs/INDEX.HTML",
+			want:   "http://example.com/docs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeVariantsCompareEqual(t *testing.T) {
+	variants := []string{
+		"http://example.com/pricing?plan=pro&period=year",
+		"http://EXAMPLE.com/pricing/?period=year&plan=pro",
+		"http://example.com/pricing?period=year&utm_campaign=launch&plan=pro#faq",
+	}
+
+	want := Normalize(variants[0])
+	for _, variant := range variants[1:] {
+		if got := Normalize(variant); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", variant, got, want)
+		}
+	}
+}
+
+func TestNormalizeInvalidURL(t *testing.T) {
+	in := "http://exa mple.com/%zz"
+	if got := Normalize(in); got != in {
+		t.Errorf("Normalize(%q) = %q, want it unchanged", in, got)
+	}
+}